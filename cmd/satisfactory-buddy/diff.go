@@ -0,0 +1,121 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/FreekingDean/satisfactory-buddy/internal/diff"
+	"github.com/FreekingDean/satisfactory-buddy/internal/parser"
+	"github.com/FreekingDean/satisfactory-buddy/internal/savefile"
+)
+
+// runDiff implements the diff subcommand:
+//
+//	satisfactory-buddy diff [-text] <old save> <new save>
+//
+// Saves may be .sav files or JSON already produced by the parser
+func runDiff(args []string) error {
+	flags := flag.NewFlagSet("diff", flag.ExitOnError)
+	text := flags.Bool("text", false, "print a human readable summary instead of JSON")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: satisfactory-buddy diff [-text] <old save> <new save>")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if flags.NArg() != 2 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	// Parser output is only kept when JSON_DIR asks for it
+	jsonDir := os.Getenv("JSON_DIR")
+	if jsonDir == "" {
+		tempDir, err := os.MkdirTemp("", "satisfactory-buddy-diff-")
+		if err != nil {
+			return fmt.Errorf("failed to create temporary directory: %w", err)
+		}
+		defer os.RemoveAll(tempDir)
+		jsonDir = tempDir
+	}
+
+	from, err := loadSave(flags.Arg(0), jsonDir)
+	if err != nil {
+		return err
+	}
+	to, err := loadSave(flags.Arg(1), jsonDir)
+	if err != nil {
+		return err
+	}
+
+	report := diff.Compare(&from, &to)
+	if *text {
+		printDiff(os.Stdout, report)
+		return nil
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
+
+// loadSave reads a parser JSON file directly, or converts a .sav file into jsonDir first
+func loadSave(path, jsonDir string) (savefile.SaveFile, error) {
+	opts := parser.Options{Strict: os.Getenv("STRICT_PARSE") == "true"}
+	if filepath.Ext(path) == ".json" {
+		return parser.Load(path, opts)
+	}
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return savefile.SaveFile{}, err
+	}
+	return parser.Parse(absPath, jsonDir, opts)
+}
+
+func printDiff(w io.Writer, report diff.Report) {
+	fmt.Fprintf(w, "%s (%s) -> %s (%s)\n",
+		report.From.SaveName, report.From.SaveDateTime.Format("2006-01-02 15:04:05"),
+		report.To.SaveName, report.To.SaveDateTime.Format("2006-01-02 15:04:05"))
+
+	for _, obj := range report.Added {
+		fmt.Fprintf(w, "+ %s %s at (%.0f, %.0f, %.0f)\n", obj.Type, obj.Name, obj.Location.X, obj.Location.Y, obj.Location.Z)
+	}
+	for _, obj := range report.Removed {
+		fmt.Fprintf(w, "- %s %s at (%.0f, %.0f, %.0f)\n", obj.Type, obj.Name, obj.Location.X, obj.Location.Y, obj.Location.Z)
+	}
+	for _, move := range report.Moved {
+		fmt.Fprintf(w, "> %s %s moved %.0f\n", move.Type, move.Name, move.Distance)
+	}
+	for _, change := range report.Changed {
+		for _, prop := range change.Properties {
+			fmt.Fprintf(w, "~ %s %s.%s: %v -> %v\n", change.Type, change.Name, prop.Name, prop.From, prop.To)
+		}
+	}
+	for _, inv := range report.Inventories {
+		items := make([]string, 0, len(inv.Items))
+		for item := range inv.Items {
+			items = append(items, item)
+		}
+		sort.Strings(items)
+		for _, item := range items {
+			fmt.Fprintf(w, "# %s %s %+d\n", inv.Name, item, inv.Items[item])
+		}
+	}
+	for _, circuit := range report.Circuits {
+		fmt.Fprintf(w, "@ %s circuit %d -> %d\n", circuit.Name, circuit.From, circuit.To)
+	}
+
+	classes := make([]string, 0, len(report.Lightweight))
+	for class := range report.Lightweight {
+		classes = append(classes, class)
+	}
+	sort.Strings(classes)
+	for _, class := range classes {
+		fmt.Fprintf(w, "%+d %s (lightweight)\n", report.Lightweight[class], class)
+	}
+}
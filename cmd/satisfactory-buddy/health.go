@@ -0,0 +1,62 @@
+package main
+
+import (
+	"time"
+
+	"github.com/FreekingDean/satisfactory-buddy/internal/metrics"
+	"github.com/FreekingDean/satisfactory-buddy/internal/parser"
+)
+
+// Health statuses reported by /health
+const (
+	statusStarting = "starting"
+	statusHealthy  = "healthy"
+	statusDegraded = "degraded"
+)
+
+// health is the /health response body
+type health struct {
+	// Status is degraded while any source, save or collector fails, even
+	// before the first save is loaded, otherwise starting until a save is
+	// loaded and healthy after
+	Status        string          `json:"status"`
+	ParserBackend string          `json:"parserBackend"`
+	Sessions      []sessionHealth `json:"sessions"`
+	LoadErrors    []loadError     `json:"loadErrors"`
+}
+
+// sessionHealth describes the snapshot currently served for a session
+type sessionHealth struct {
+	Server          string                   `json:"server"`
+	Session         string                   `json:"session"`
+	SaveName        string                   `json:"saveName"`
+	SaveTime        time.Time                `json:"saveTime"`
+	SaveAgeSeconds  float64                  `json:"saveAgeSeconds"`
+	ParsedAt        time.Time                `json:"parsedAt"`
+	ParseSeconds    float64                  `json:"parseSeconds"`
+	Objects         int                      `json:"objects"`
+	CollectorErrors []metrics.CollectorError `json:"collectorErrors,omitempty"`
+}
+
+// checkHealth reports the state of every source and loaded session
+func checkHealth() health {
+	h := health{
+		Status:        statusHealthy,
+		ParserBackend: parser.Backend,
+		Sessions:      listSessionHealth(),
+		LoadErrors:    listLoadErrors(),
+	}
+
+	if len(h.Sessions) == 0 {
+		h.Status = statusStarting
+	}
+	for _, session := range h.Sessions {
+		if len(session.CollectorErrors) > 0 {
+			h.Status = statusDegraded
+		}
+	}
+	if len(h.LoadErrors) > 0 {
+		h.Status = statusDegraded
+	}
+	return h
+}
@@ -0,0 +1,676 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/FreekingDean/satisfactory-buddy/internal/alerts"
+	"github.com/FreekingDean/satisfactory-buddy/internal/archive"
+	"github.com/FreekingDean/satisfactory-buddy/internal/catalog"
+	"github.com/FreekingDean/satisfactory-buddy/internal/config"
+	"github.com/FreekingDean/satisfactory-buddy/internal/history"
+	"github.com/FreekingDean/satisfactory-buddy/internal/metrics"
+	"github.com/FreekingDean/satisfactory-buddy/internal/parser"
+	"github.com/FreekingDean/satisfactory-buddy/internal/savefile"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// saveKey identifies the latest save of one session on one server
+type saveKey struct {
+	Server  string `json:"server"`
+	Session string `json:"session"`
+}
+
+// latestSaves holds the most recently parsed save of every session for the JSON endpoints
+var (
+	latestMu    sync.RWMutex
+	latestSaves = make(map[saveKey]*snapshot)
+)
+
+// snapshot is the latest parsed save of a session and how it was loaded
+type snapshot struct {
+	saveFile        *savefile.SaveFile
+	parsedAt        time.Time
+	parseDuration   time.Duration
+	collectorErrors []metrics.CollectorError
+}
+
+// Failed parses are retried after retryMin, doubling up to retryMax, while the
+// last good snapshot of the session keeps being served
+const (
+	retryMin = 5 * time.Second
+	retryMax = 10 * time.Minute
+)
+
+// loadErrors holds the last error of every source and session that failed to
+// load, with an empty session for directory read errors
+var (
+	errorsMu   sync.RWMutex
+	loadErrors = make(map[saveKey]loadError)
+)
+
+// loadError describes a source or session that currently fails to load
+type loadError struct {
+	Server   string    `json:"server"`
+	Session  string    `json:"session,omitempty"`
+	Error    string    `json:"error"`
+	Attempts int       `json:"attempts"`
+	Since    time.Time `json:"since"`
+}
+
+// supervisor runs one loader per configured source, applies reloaded
+// configurations to them and evaluates alerts once their loads finish
+type supervisor struct {
+	mu        sync.Mutex
+	current   config.Config
+	store     *archive.Store
+	samples   *history.Store
+	evaluator *alerts.Evaluator
+	loaders   map[string]*loader
+
+	// updates is held for reading by loaders while they change metrics and
+	// for writing by alert evaluation, so alerts never see a session whose
+	// metrics are half updated
+	updates sync.RWMutex
+	// loaded is signalled by loaders after every load
+	loaded chan struct{}
+}
+
+func newSupervisor(cfg config.Config, store *archive.Store, samples *history.Store, evaluator *alerts.Evaluator) *supervisor {
+	return &supervisor{
+		current:   cfg,
+		store:     store,
+		samples:   samples,
+		evaluator: evaluator,
+		loaders:   make(map[string]*loader),
+		loaded:    make(chan struct{}, 1),
+	}
+}
+
+// evaluateAlerts evaluates the alert rules after loads, waiting until no
+// loader is updating metrics. Loads finishing together share one evaluation.
+func (s *supervisor) evaluateAlerts() {
+	for range s.loaded {
+		s.updates.Lock()
+		err := s.evaluator.Evaluate(prometheus.DefaultGatherer)
+		s.updates.Unlock()
+		if err != nil {
+			log.Printf("Warning: failed to evaluate alerts: %v", err)
+		}
+	}
+}
+
+// apply starts, updates and stops loaders to match cfg, which must be valid.
+// Loaders being replaced are told to stop without waiting for their current
+// load; their replacements wait for them before loading.
+func (s *supervisor) apply(cfg config.Config) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := catalog.SetOverrides(cfg.CatalogOverrides()); err != nil {
+		return fmt.Errorf("failed to apply catalog overrides: %w", err)
+	}
+	if len(s.loaders) > 0 {
+		s.warnRestart(cfg)
+	}
+	s.evaluator.SetRules(cfg.Alerts)
+
+	wanted := make(map[string]config.Source, len(cfg.Sources))
+	for _, src := range cfg.Sources {
+		wanted[src.Server] = src
+	}
+	stopping := make(map[string]*loader)
+	for server, l := range s.loaders {
+		if src, ok := wanted[server]; !ok || src != l.source {
+			log.Printf("[%s] Save source removed or changed, stopping its loader", server)
+			l.stop()
+			stopping[server] = l
+			delete(s.loaders, server)
+		}
+	}
+
+	settings := loaderSettings{
+		interval:       cfg.Interval,
+		parseOptions:   parser.Options{Strict: cfg.Strict},
+		metricsOptions: cfg.MetricsOptions(),
+	}
+	for _, src := range cfg.Sources {
+		if l, ok := s.loaders[src.Server]; ok {
+			l.setSettings(settings)
+			continue
+		}
+		// The JSON directory, archive and history only change on restart
+		l := newLoader(src, s.current.JSONDir, settings, s.store, s.current.Archive.Summary, s.samples, &s.updates, s.loaded)
+		if previous, ok := stopping[src.Server]; ok {
+			l.previous = previous.stopped
+		}
+		s.loaders[src.Server] = l
+		go l.run()
+	}
+
+	s.current.Sources = cfg.Sources
+	s.current.Collectors = cfg.Collectors
+	s.current.Labels = cfg.Labels
+	s.current.Interval = cfg.Interval
+	s.current.Strict = cfg.Strict
+	s.current.Catalog = cfg.Catalog
+	s.current.Alerts = cfg.Alerts
+	return nil
+}
+
+// warnRestart logs settings in cfg that only take effect after a restart
+func (s *supervisor) warnRestart(cfg config.Config) {
+	changed := map[string]bool{
+		"listen":         cfg.Listen != s.current.Listen,
+		"jsonDir":        cfg.JSONDir != s.current.JSONDir,
+		"mapPoints":      cfg.MapPoints != s.current.MapPoints,
+		"saveTimestamps": cfg.SaveTimestamps != s.current.SaveTimestamps,
+		"archive":        cfg.Archive != s.current.Archive,
+		"history":        cfg.History != s.current.History,
+	}
+	for _, name := range []string{"listen", "jsonDir", "mapPoints", "saveTimestamps", "archive", "history"} {
+		if changed[name] {
+			log.Printf("Warning: %s changed, restart to apply it", name)
+		}
+	}
+}
+
+// loaderSettings are the loader options that can change on reload
+type loaderSettings struct {
+	interval       time.Duration
+	parseOptions   parser.Options
+	metricsOptions metrics.Options
+}
+
+// loader parses the newest save of every session found in one source
+type loader struct {
+	source           config.Source
+	jsonPath         string
+	store            *archive.Store
+	archiveSummaries bool
+	samples          *history.Store
+	updates          *sync.RWMutex
+	loaded           chan<- struct{}
+
+	mu       sync.Mutex
+	settings loaderSettings
+	done     chan struct{}
+	stopped  chan struct{}
+	// previous is closed once the loader this one replaces has stopped
+	previous <-chan struct{}
+
+	// parsed tracks the file last parsed for each session in the source
+	parsed map[string]parsedFile
+	// failures tracks the sessions whose newest file failed to parse
+	failures map[string]*failure
+}
+
+// failure is a save file that failed to parse and when to try it again
+type failure struct {
+	path     string
+	modTime  time.Time
+	attempts int
+	retryAt  time.Time
+}
+
+type parsedFile struct {
+	path     string
+	modTime  time.Time
+	saveFile *savefile.SaveFile
+}
+
+// candidate is the newest save file seen so far for a session
+type candidate struct {
+	path     string
+	name     string
+	modTime  time.Time
+	saveTime time.Time
+}
+
+func newLoader(src config.Source, jsonPath string, settings loaderSettings, store *archive.Store, archiveSummaries bool, samples *history.Store, updates *sync.RWMutex, loaded chan<- struct{}) *loader {
+	if jsonPath != "" {
+		// Keep parser output apart when servers use the same save names
+		jsonPath = filepath.Join(jsonPath, src.Server)
+		if err := os.MkdirAll(jsonPath, 0o755); err != nil {
+			log.Printf("Warning: failed to create JSON directory: %v", err)
+		}
+	}
+	l := &loader{
+		source:           src,
+		jsonPath:         jsonPath,
+		store:            store,
+		archiveSummaries: archiveSummaries,
+		samples:          samples,
+		updates:          updates,
+		loaded:           loaded,
+		done:             make(chan struct{}),
+		stopped:          make(chan struct{}),
+		parsed:           make(map[string]parsedFile),
+		failures:         make(map[string]*failure),
+	}
+	l.setSettings(settings)
+	return l
+}
+
+func (l *loader) setSettings(settings loaderSettings) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	settings.metricsOptions.Server = l.source.Server
+	l.settings = settings
+}
+
+func (l *loader) currentSettings() loaderSettings {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.settings
+}
+
+// stop makes run return after the current load without waiting for it;
+// stopped is closed once run has removed this loader's metrics
+func (l *loader) stop() {
+	close(l.done)
+}
+
+// run loads the source immediately and then every interval until stopped,
+// when it removes the metrics of every session it loaded. After every load
+// it asks the supervisor to evaluate alerts.
+func (l *loader) run() {
+	defer close(l.stopped)
+	if l.previous != nil {
+		// Let the replaced loader remove its metrics before adding ours
+		<-l.previous
+	}
+	for {
+		l.load()
+		select {
+		case l.loaded <- struct{}{}:
+		default:
+			// An evaluation is already pending and will see this load
+		}
+
+		select {
+		case <-l.done:
+			for session, parsed := range l.parsed {
+				metrics.Forget(l.source.Server, parsed.saveFile.Header.SessionName)
+				deleteLatestSave(saveKey{l.source.Server, parsed.saveFile.Header.SessionName})
+				delete(l.parsed, session)
+			}
+			for session := range l.failures {
+				l.forgetFailure(session)
+			}
+			metrics.Forget(l.source.Server, "")
+			clearLoadError(saveKey{l.source.Server, ""})
+			return
+		case <-time.After(l.nextLoad(l.currentSettings().interval)):
+		}
+	}
+}
+
+// nextLoad returns how long to wait before the next load, which is sooner
+// than interval when a failed parse is due to be retried
+func (l *loader) nextLoad(interval time.Duration) time.Duration {
+	wait := interval
+	for _, f := range l.failures {
+		if until := time.Until(f.retryAt); until < wait {
+			wait = max(until, 0)
+		}
+	}
+	return wait
+}
+
+// load parses the newest save of each session that changed since the last
+// load and refreshes the metrics of every session
+func (l *loader) load() {
+	log.Printf("[%s] Loading save files from directory: %s", l.source.Server, l.source.Dir)
+	entries, err := os.ReadDir(l.source.Dir)
+	dirKey := saveKey{l.source.Server, ""}
+	if err != nil {
+		// Keep serving the sessions already loaded until the directory is back
+		log.Printf("[%s] Warning: failed to read save directory: %v", l.source.Server, err)
+		metrics.ParseFailed(l.source.Server, "")
+		setLoadError(dirKey, err)
+		return
+	}
+	clearLoadError(dirKey)
+
+	// Find the newest save file of every session
+	newest := make(map[string]candidate)
+	for _, entry := range entries {
+		log.Printf("[%s] Found file: %s", l.source.Server, entry.Name())
+		if !entry.Type().IsRegular() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			log.Printf("Error getting file info: %v", err)
+			continue
+		}
+
+		path := filepath.Join(l.source.Dir, entry.Name())
+		c := candidate{path: path, name: entry.Name(), modTime: info.ModTime(), saveTime: info.ModTime()}
+		session := sessionFromFileName(entry.Name())
+		if header, err := parser.ReadHeader(path); err == nil {
+			session = header.SessionName
+			c.saveTime = header.SaveDateTime
+		} else {
+			log.Printf("Warning: could not read header of %s, grouping by file name: %v", entry.Name(), err)
+		}
+
+		if current, ok := newest[session]; !ok || c.saveTime.After(current.saveTime) ||
+			(c.saveTime.Equal(current.saveTime) && c.modTime.After(current.modTime)) {
+			newest[session] = c
+		}
+	}
+	if len(newest) == 0 {
+		log.Printf("[%s] No save files found", l.source.Server)
+	}
+
+	sessions := make([]string, 0, len(newest))
+	for session := range newest {
+		sessions = append(sessions, session)
+	}
+	sort.Strings(sessions)
+	for _, session := range sessions {
+		l.loadSession(session, newest[session])
+	}
+
+	// Drop sessions whose saves were removed from the source
+	for session, parsed := range l.parsed {
+		if _, ok := newest[session]; ok {
+			continue
+		}
+		log.Printf("[%s] Session %q no longer has saves, removing its metrics", l.source.Server, session)
+		metrics.Forget(l.source.Server, parsed.saveFile.Header.SessionName)
+		deleteLatestSave(saveKey{l.source.Server, parsed.saveFile.Header.SessionName})
+		delete(l.parsed, session)
+	}
+	for session := range l.failures {
+		if _, ok := newest[session]; !ok {
+			l.forgetFailure(session)
+		}
+	}
+}
+
+// loadSession parses a session's newest save when it changed, then updates its metrics
+func (l *loader) loadSession(session string, c candidate) {
+	settings := l.currentSettings()
+	parsed, ok := l.parsed[session]
+	if (!ok || parsed.path != c.path || !parsed.modTime.Equal(c.modTime)) && l.shouldParse(session, c) {
+		start := time.Now()
+		saveFile, err := parser.Parse(c.path, l.jsonPath, settings.parseOptions)
+		if err == nil {
+			parsed = l.parseSucceeded(session, c, &saveFile, time.Since(start))
+		} else {
+			l.parseFailed(session, c, err)
+		}
+	}
+
+	if parsed.saveFile == nil {
+		// The session's only save failed to parse and is waiting for a retry
+		return
+	}
+
+	// Create metrics collector. After a failed parse this keeps reporting the
+	// last good snapshot until the retry succeeds.
+	collector := metrics.NewMetricsCollector(parsed.saveFile, settings.metricsOptions)
+	l.updates.RLock()
+	errs := collector.UpdateMetrics()
+	l.updates.RUnlock()
+	setCollectorErrors(saveKey{l.source.Server, parsed.saveFile.Header.SessionName}, errs)
+}
+
+// parseSucceeded makes saveFile the session's snapshot
+func (l *loader) parseSucceeded(session string, c candidate, saveFile *savefile.SaveFile, duration time.Duration) parsedFile {
+	log.Printf("✅ Successfully loaded save file: %s", saveFile.Header.SaveName)
+	log.Printf("📊 Save contains %d levels with buildings", len(saveFile.Levels))
+	l.forgetFailure(session)
+	metrics.ParseSucceeded(l.source.Server, saveFile.Header.SessionName, time.Now())
+	metrics.ObserveLoad(l.source.Server, saveFile.Header.SessionName, saveFile.LoadStats())
+
+	if previous, ok := l.parsed[session]; ok && previous.saveFile.Header.SessionName != saveFile.Header.SessionName {
+		metrics.Forget(l.source.Server, previous.saveFile.Header.SessionName)
+		deleteLatestSave(saveKey{l.source.Server, previous.saveFile.Header.SessionName})
+	}
+	parsed := parsedFile{path: c.path, modTime: c.modTime, saveFile: saveFile}
+	l.parsed[session] = parsed
+	setLatestSave(saveKey{l.source.Server, saveFile.Header.SessionName}, &snapshot{
+		saveFile:      saveFile,
+		parsedAt:      time.Now(),
+		parseDuration: duration,
+	})
+
+	l.archive(c, saveFile)
+	l.record(saveFile)
+	return parsed
+}
+
+// shouldParse reports whether c is not a file that failed to parse and is
+// still waiting for its retry
+func (l *loader) shouldParse(session string, c candidate) bool {
+	f, ok := l.failures[session]
+	if !ok || f.path != c.path || !f.modTime.Equal(c.modTime) {
+		return true
+	}
+	return !time.Now().Before(f.retryAt)
+}
+
+// parseFailed records a failed parse and schedules its retry with backoff
+func (l *loader) parseFailed(session string, c candidate, err error) {
+	f, ok := l.failures[session]
+	if !ok || f.path != c.path || !f.modTime.Equal(c.modTime) {
+		// A newer file starts the backoff over
+		f = &failure{path: c.path, modTime: c.modTime}
+		l.failures[session] = f
+	}
+	f.attempts++
+	delay := retryMin
+	for i := 1; i < f.attempts && delay < retryMax; i++ {
+		delay *= 2
+	}
+	delay = min(delay, retryMax)
+	f.retryAt = time.Now().Add(delay)
+
+	log.Printf("[%s] Warning: failed to parse %s, retrying in %s: %v", l.source.Server, c.name, delay, err)
+	metrics.ParseFailed(l.source.Server, session)
+	setLoadError(saveKey{l.source.Server, session}, err)
+}
+
+// forgetFailure drops the failure of a session that parsed or disappeared
+func (l *loader) forgetFailure(session string) {
+	if _, ok := l.failures[session]; !ok {
+		return
+	}
+	delete(l.failures, session)
+	clearLoadError(saveKey{l.source.Server, session})
+}
+
+func (l *loader) archive(c candidate, saveFile *savefile.SaveFile) {
+	if l.store == nil {
+		return
+	}
+
+	entry, summary := archive.Summarize(saveFile)
+	entry.FileName = c.name
+	entry.Server = l.source.Server
+	var summaryValue interface{}
+	if l.archiveSummaries {
+		summaryValue = summary
+	}
+	if entry, added, err := l.store.Add(c.path, entry, summaryValue); err != nil {
+		log.Printf("Warning: failed to archive save file: %v", err)
+	} else if added {
+		log.Printf("Archived save file %s as %s", c.name, entry.Hash)
+	}
+}
+
+func (l *loader) record(saveFile *savefile.SaveFile) {
+	if l.samples == nil {
+		return
+	}
+	sample := history.Derive(saveFile)
+	sample.Server = l.source.Server
+	if _, err := l.samples.Record(sample); err != nil {
+		log.Printf("Warning: failed to record history: %v", err)
+	}
+}
+
+// saveFileSuffix matches the rotation suffix of autosaves and numbered saves
+var saveFileSuffix = regexp.MustCompile(`(_autosave)?_\d+$`)
+
+// sessionFromFileName guesses the session of a save whose header cannot be read
+func sessionFromFileName(name string) string {
+	return saveFileSuffix.ReplaceAllString(strings.TrimSuffix(name, filepath.Ext(name)), "")
+}
+
+// setLoadError records err for key, counting repeated failures
+func setLoadError(key saveKey, err error) {
+	errorsMu.Lock()
+	defer errorsMu.Unlock()
+	current, ok := loadErrors[key]
+	if !ok {
+		current = loadError{Server: key.Server, Session: key.Session, Since: time.Now()}
+	}
+	current.Error = err.Error()
+	current.Attempts++
+	loadErrors[key] = current
+}
+
+func clearLoadError(key saveKey) {
+	errorsMu.Lock()
+	defer errorsMu.Unlock()
+	delete(loadErrors, key)
+}
+
+// listLoadErrors returns every current load error sorted by server and session
+func listLoadErrors() []loadError {
+	errorsMu.RLock()
+	defer errorsMu.RUnlock()
+
+	errs := make([]loadError, 0, len(loadErrors))
+	for _, err := range loadErrors {
+		errs = append(errs, err)
+	}
+	sort.Slice(errs, func(i, j int) bool {
+		if errs[i].Server == errs[j].Server {
+			return errs[i].Session < errs[j].Session
+		}
+		return errs[i].Server < errs[j].Server
+	})
+	return errs
+}
+
+func setLatestSave(key saveKey, snap *snapshot) {
+	latestMu.Lock()
+	defer latestMu.Unlock()
+	latestSaves[key] = snap
+}
+
+// setCollectorErrors records the collectors that failed in a session's last update
+func setCollectorErrors(key saveKey, errs []metrics.CollectorError) {
+	latestMu.Lock()
+	defer latestMu.Unlock()
+	if snap, ok := latestSaves[key]; ok {
+		snap.collectorErrors = errs
+	}
+}
+
+func deleteLatestSave(key saveKey) {
+	latestMu.Lock()
+	defer latestMu.Unlock()
+	delete(latestSaves, key)
+}
+
+// getSave returns the latest save of a session, or nil if none was loaded
+func getSave(server, session string) *savefile.SaveFile {
+	latestMu.RLock()
+	defer latestMu.RUnlock()
+	if snap, ok := latestSaves[saveKey{server, session}]; ok {
+		return snap.saveFile
+	}
+	return nil
+}
+
+// getLatestSave returns the most recently written save of any session matching
+// the server and session, where empty values match everything
+func getLatestSave(server, session string) *savefile.SaveFile {
+	latestMu.RLock()
+	defer latestMu.RUnlock()
+
+	var latest *savefile.SaveFile
+	for key, snap := range latestSaves {
+		if (server != "" && key.Server != server) || (session != "" && key.Session != session) {
+			continue
+		}
+		if latest == nil || snap.saveFile.Header.SaveDateTime.After(latest.Header.SaveDateTime.Time) {
+			latest = snap.saveFile
+		}
+	}
+	return latest
+}
+
+// listSessions returns every loaded session with its save name and time
+func listSessions() []map[string]interface{} {
+	latestMu.RLock()
+	defer latestMu.RUnlock()
+
+	keys := sortedSaveKeys()
+	sessions := make([]map[string]interface{}, 0, len(keys))
+	for _, key := range keys {
+		header := latestSaves[key].saveFile.Header
+		sessions = append(sessions, map[string]interface{}{
+			"server":   key.Server,
+			"session":  key.Session,
+			"saveName": header.SaveName,
+			"saveTime": header.SaveDateTime.Time,
+		})
+	}
+	return sessions
+}
+
+// listSessionHealth returns how every loaded session was last loaded
+func listSessionHealth() []sessionHealth {
+	latestMu.RLock()
+	defer latestMu.RUnlock()
+
+	keys := sortedSaveKeys()
+	sessions := make([]sessionHealth, 0, len(keys))
+	for _, key := range keys {
+		snap := latestSaves[key]
+		header := snap.saveFile.Header
+		sessions = append(sessions, sessionHealth{
+			Server:          key.Server,
+			Session:         key.Session,
+			SaveName:        header.SaveName,
+			SaveTime:        header.SaveDateTime.Time,
+			SaveAgeSeconds:  time.Since(header.SaveDateTime.Time).Seconds(),
+			ParsedAt:        snap.parsedAt,
+			ParseSeconds:    snap.parseDuration.Seconds(),
+			Objects:         len(snap.saveFile.AllGameObjects()),
+			CollectorErrors: snap.collectorErrors,
+		})
+	}
+	return sessions
+}
+
+// sortedSaveKeys returns the keys of latestSaves by server and session. The
+// caller must hold latestMu.
+func sortedSaveKeys() []saveKey {
+	keys := make([]saveKey, 0, len(latestSaves))
+	for key := range latestSaves {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Server == keys[j].Server {
+			return keys[i].Session < keys[j].Session
+		}
+		return keys[i].Server < keys[j].Server
+	})
+	return keys
+}
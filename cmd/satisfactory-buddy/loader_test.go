@@ -0,0 +1,111 @@
+package main
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/FreekingDean/satisfactory-buddy/internal/config"
+)
+
+func TestParseFailedBackoff(t *testing.T) {
+	modTime := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	first := candidate{path: "/saves/a_autosave_0.sav", name: "a_autosave_0.sav", modTime: modTime}
+	touched := candidate{path: first.path, name: first.name, modTime: modTime.Add(time.Minute)}
+	newer := candidate{path: "/saves/a_autosave_1.sav", name: "a_autosave_1.sav", modTime: modTime.Add(time.Hour)}
+
+	type attempt struct {
+		session string
+		file    candidate
+		delay   time.Duration
+	}
+	repeat := func(file candidate, delays ...time.Duration) []attempt {
+		attempts := make([]attempt, len(delays))
+		for i, delay := range delays {
+			attempts[i] = attempt{"a", file, delay}
+		}
+		return attempts
+	}
+	tests := []struct {
+		name     string
+		attempts []attempt
+	}{
+		{
+			name: "doubles up to the cap",
+			attempts: repeat(first,
+				5*time.Second, 10*time.Second, 20*time.Second, 40*time.Second, 80*time.Second,
+				160*time.Second, 320*time.Second, retryMax, retryMax,
+			),
+		},
+		{
+			name:     "newer file starts over",
+			attempts: append(repeat(first, 5*time.Second, 10*time.Second, 20*time.Second), repeat(newer, 5*time.Second, 10*time.Second)...),
+		},
+		{
+			name:     "rewritten file starts over",
+			attempts: append(repeat(first, 5*time.Second, 10*time.Second), repeat(touched, 5*time.Second)...),
+		},
+		{
+			name: "sessions back off separately",
+			attempts: []attempt{
+				{"a", first, 5 * time.Second},
+				{"a", first, 10 * time.Second},
+				{"b", first, 5 * time.Second},
+				{"a", first, 20 * time.Second},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := &loader{source: config.Source{Server: "test"}, failures: make(map[string]*failure)}
+			t.Cleanup(func() {
+				for session := range l.failures {
+					l.forgetFailure(session)
+				}
+			})
+
+			for i, a := range tt.attempts {
+				before := time.Now()
+				l.parseFailed(a.session, a.file, errors.New("bad save"))
+				after := time.Now()
+
+				f := l.failures[a.session]
+				if f.retryAt.Before(before.Add(a.delay)) || f.retryAt.After(after.Add(a.delay)) {
+					t.Errorf("attempt %d: retry in %s, want %s", i, f.retryAt.Sub(before).Round(time.Second), a.delay)
+				}
+				if l.shouldParse(a.session, a.file) {
+					t.Errorf("attempt %d: shouldParse() = true while waiting to retry", i)
+				}
+			}
+		})
+	}
+}
+
+func TestShouldParse(t *testing.T) {
+	modTime := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	failed := candidate{path: "/saves/a_autosave_0.sav", modTime: modTime}
+
+	tests := []struct {
+		name    string
+		retryAt time.Duration
+		session string
+		file    candidate
+		want    bool
+	}{
+		{name: "waiting to retry", retryAt: time.Minute, session: "a", file: failed, want: false},
+		{name: "retry due", retryAt: -time.Second, session: "a", file: failed, want: true},
+		{name: "newer file", retryAt: time.Minute, session: "a", file: candidate{path: "/saves/a_autosave_1.sav", modTime: modTime}, want: true},
+		{name: "rewritten file", retryAt: time.Minute, session: "a", file: candidate{path: failed.path, modTime: modTime.Add(time.Second)}, want: true},
+		{name: "other session", retryAt: time.Minute, session: "b", file: failed, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := &loader{failures: map[string]*failure{
+				"a": {path: failed.path, modTime: failed.modTime, attempts: 1, retryAt: time.Now().Add(tt.retryAt)},
+			}}
+			if got := l.shouldParse(tt.session, tt.file); got != tt.want {
+				t.Errorf("shouldParse() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,43 +1,354 @@
 package main
 
 import (
+	"encoding/json"
+	"flag"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
 	"time"
 
+	"github.com/FreekingDean/satisfactory-buddy/internal/alerts"
+	"github.com/FreekingDean/satisfactory-buddy/internal/api"
+	"github.com/FreekingDean/satisfactory-buddy/internal/archive"
+	"github.com/FreekingDean/satisfactory-buddy/internal/collectibles"
+	"github.com/FreekingDean/satisfactory-buddy/internal/config"
+	"github.com/FreekingDean/satisfactory-buddy/internal/history"
 	"github.com/FreekingDean/satisfactory-buddy/internal/metrics"
 	"github.com/FreekingDean/satisfactory-buddy/internal/parser"
+	"github.com/FreekingDean/satisfactory-buddy/internal/players"
+	"github.com/FreekingDean/satisfactory-buddy/internal/savefile"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "diff" {
+		if err := runDiff(os.Args[2:]); err != nil {
+			log.Fatalf("Failed to diff saves: %v", err)
+		}
+		return
+	}
+
+	flags := config.RegisterFlags(flag.CommandLine)
+	flag.Parse()
+
 	log.Println("Starting Satisfactory Metrics Server...")
 
-	dirPath := os.Getenv("SAVES_DIR")
-	jsonPath := os.Getenv("JSON_DIR")
+	cfg, err := flags.Load()
+	if err != nil {
+		log.Fatalf("Invalid configuration:\n%v", err)
+	}
 
-	loadAndServeMetrics(dirPath, jsonPath)
+	if err := collectibles.LoadMapPoints(cfg.MapPoints); err != nil {
+		log.Printf("Warning: collectibles will not be tracked: %v", err)
+	}
 
-	// Set up periodic updates (every 30 seconds)
-	// In a real scenario, you'd reload the save file periodically
-	go func() {
-		ticker := time.NewTicker(30 * time.Second)
-		defer ticker.Stop()
+	var store *archive.Store
+	if cfg.Archive.Dir != "" {
+		if store, err = archive.Open(cfg.Archive.Dir, cfg.RetentionPolicy()); err != nil {
+			log.Fatalf("Failed to open archive: %v", err)
+		}
+	}
+
+	var samples *history.Store
+	if cfg.History.Path != "" {
+		if samples, err = history.Open(cfg.History.Path); err != nil {
+			log.Fatalf("Failed to open history: %v", err)
+		}
+		if store != nil && cfg.History.Backfill {
+			go backfillHistory(samples, store, parser.Options{Strict: cfg.Strict})
+		}
+	}
 
-		for range ticker.C {
-			loadAndServeMetrics(dirPath, jsonPath)
+	evaluator := &alerts.Evaluator{}
+	sup := newSupervisor(cfg, store, samples, evaluator)
+	if err := sup.apply(cfg); err != nil {
+		log.Fatalf("Failed to apply configuration: %v", err)
+	}
+	go sup.evaluateAlerts()
+
+	// Reload the configuration on SIGHUP, keeping the running one when it is invalid
+	go func() {
+		hup := make(chan os.Signal, 1)
+		signal.Notify(hup, syscall.SIGHUP)
+		for range hup {
+			log.Println("Reloading configuration...")
+			reloaded, err := flags.Load()
+			if err != nil {
+				log.Printf("Warning: keeping previous configuration:\n%v", err)
+				continue
+			}
+			if err := sup.apply(reloaded); err != nil {
+				log.Printf("Warning: keeping previous configuration: %v", err)
+				continue
+			}
+			log.Println("Configuration reloaded")
 		}
 	}()
 
-	// Set up HTTP server for Prometheus metrics
-	http.Handle("/metrics", promhttp.Handler())
+	// Set up HTTP server for Prometheus metrics, optionally stamping samples
+	// with the save's own timestamp in the OpenMetrics format
+	if cfg.SaveTimestamps {
+		gatherer := metrics.NewTimestampGatherer(prometheus.DefaultGatherer, func(server, session string) time.Time {
+			if saveFile := getSave(server, session); saveFile != nil {
+				return saveFile.Header.SaveDateTime.Time
+			}
+			return time.Time{}
+		})
+		http.Handle("/metrics", promhttp.InstrumentMetricHandler(
+			prometheus.DefaultRegisterer,
+			promhttp.HandlerFor(gatherer, promhttp.HandlerOpts{EnableOpenMetrics: true}),
+		))
+	} else {
+		http.Handle("/metrics", promhttp.Handler())
+	}
 
-	// Add a health check endpoint
+	// Add a health check endpoint answering 503 while degraded. Load and
+	// collector errors do not affect /ready, since the last good snapshot of
+	// every session is still served.
 	http.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		h := checkHealth()
+		if h.Status == statusDegraded {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		writeJSON(w, h)
+	})
+
+	// Add a readiness endpoint that succeeds once a save has been loaded
+	http.HandleFunc("/ready", func(w http.ResponseWriter, r *http.Request) {
+		if getLatestSave("", "") == nil {
+			http.Error(w, "no save file loaded yet", http.StatusServiceUnavailable)
+			return
+		}
 		w.WriteHeader(http.StatusOK)
-		fmt.Fprintf(w, "Satisfactory Metrics Server is healthy\n")
+		fmt.Fprintf(w, "Satisfactory Metrics Server is ready\n")
+	})
+
+	// Add a sessions endpoint listing the latest save of every server and session.
+	// The JSON endpoints below accept ?server=&session= to pick one, defaulting
+	// to the most recently written save.
+	http.HandleFunc("/sessions", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, listSessions())
+	})
+
+	// Add a collectibles endpoint listing every known collectible and its state
+	http.HandleFunc("/collectibles", func(w http.ResponseWriter, r *http.Request) {
+		saveFile := requestSave(r)
+		if saveFile == nil {
+			http.Error(w, "no save file loaded yet", http.StatusServiceUnavailable)
+			return
+		}
+
+		items := collectibles.Track(saveFile)
+		writeJSON(w, map[string]interface{}{
+			"summary":      collectibles.Summarize(items),
+			"collectibles": items,
+		})
+	})
+
+	// Add a drop pod report sorted by distance from the HUB or ?x=&y=&z=,
+	// which are required before the HUB is built
+	http.HandleFunc("/drop-pods", func(w http.ResponseWriter, r *http.Request) {
+		saveFile := requestSave(r)
+		if saveFile == nil {
+			http.Error(w, "no save file loaded yet", http.StatusServiceUnavailable)
+			return
+		}
+
+		origin, hasHub := collectibles.HubLocation(saveFile)
+		query := r.URL.Query()
+		if query.Has("x") || query.Has("y") || query.Has("z") {
+			var err error
+			if origin, err = parseVector(query.Get("x"), query.Get("y"), query.Get("z")); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		} else if !hasHub {
+			http.Error(w, "no HUB built yet, pass ?x=&y=&z= to measure distances from", http.StatusBadRequest)
+			return
+		}
+
+		writeJSON(w, collectibles.DropPods(saveFile, origin))
+	})
+
+	// Add a players endpoint with position, health and inventories
+	http.HandleFunc("/players", func(w http.ResponseWriter, r *http.Request) {
+		saveFile := requestSave(r)
+		if saveFile == nil {
+			http.Error(w, "no save file loaded yet", http.StatusServiceUnavailable)
+			return
+		}
+
+		writeJSON(w, players.Players(saveFile))
+	})
+
+	// Add a spatial query endpoint. Coordinates are world units (100 per meter):
+	//   ?x=&y=&z=&radius=     objects within radius of the point
+	//   ?x=&y=&z=&nearest=    the n objects nearest the point
+	//   ?min_x=..&max_z=      objects inside a bounding box
+	// Each may be filtered with one or more &type= class names or type paths.
+	http.HandleFunc("/spatial", func(w http.ResponseWriter, r *http.Request) {
+		saveFile := requestSave(r)
+		if saveFile == nil {
+			http.Error(w, "no save file loaded yet", http.StatusServiceUnavailable)
+			return
+		}
+
+		query := r.URL.Query()
+		types := query["type"]
+
+		if query.Has("min_x") || query.Has("max_x") {
+			min, err := parseVector(query.Get("min_x"), query.Get("min_y"), query.Get("min_z"))
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			max, err := parseVector(query.Get("max_x"), query.Get("max_y"), query.Get("max_z"))
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			writeJSON(w, saveFile.InBox(min, max, types...))
+			return
+		}
+
+		point, err := parseVector(query.Get("x"), query.Get("y"), query.Get("z"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if query.Has("radius") {
+			radius, err := strconv.ParseFloat(query.Get("radius"), 64)
+			if err != nil || !api.IsFinite(radius) || radius < 0 {
+				http.Error(w, fmt.Sprintf("invalid radius %q", query.Get("radius")), http.StatusBadRequest)
+				return
+			}
+			writeJSON(w, saveFile.Within(point, radius, types...))
+			return
+		}
+
+		nearest := 10
+		if query.Has("nearest") {
+			if nearest, err = strconv.Atoi(query.Get("nearest")); err != nil || nearest <= 0 {
+				http.Error(w, fmt.Sprintf("invalid nearest %q", query.Get("nearest")), http.StatusBadRequest)
+				return
+			}
+		}
+		writeJSON(w, saveFile.Nearest(point, nearest, types...))
+	})
+
+	// Add archive endpoints listing archived saves and serving their contents
+	http.HandleFunc("/archive", func(w http.ResponseWriter, r *http.Request) {
+		if store == nil {
+			http.Error(w, "archive is disabled, set ARCHIVE_DIR to enable it", http.StatusNotFound)
+			return
+		}
+		writeJSON(w, store.List())
+	})
+
+	http.HandleFunc("/archive/{hash}", func(w http.ResponseWriter, r *http.Request) {
+		if store == nil {
+			http.Error(w, "archive is disabled, set ARCHIVE_DIR to enable it", http.StatusNotFound)
+			return
+		}
+
+		entry, err := store.Get(r.PathValue("hash"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		path, err := store.Path(entry.Hash)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", entry.FileName))
+		http.ServeFile(w, r, path)
+	})
+
+	http.HandleFunc("/archive/{hash}/summary", func(w http.ResponseWriter, r *http.Request) {
+		if store == nil {
+			http.Error(w, "archive is disabled, set ARCHIVE_DIR to enable it", http.StatusNotFound)
+			return
+		}
+
+		var summary archive.Summary
+		if err := store.Summary(r.PathValue("hash"), &summary); err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		writeJSON(w, summary)
+	})
+
+	// Add a history range query endpoint:
+	//   ?series=&series=&server=&session=&from=&to=
+	// from and to are RFC 3339 times or Unix seconds, and may be omitted
+	http.HandleFunc("/history", func(w http.ResponseWriter, r *http.Request) {
+		if samples == nil {
+			http.Error(w, "history is disabled, set HISTORY_PATH to enable it", http.StatusNotFound)
+			return
+		}
+
+		query := r.URL.Query()
+		from, err := parseTime(query.Get("from"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		to, err := parseTime(query.Get("to"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		writeJSON(w, samples.Query(query.Get("server"), query.Get("session"), query["series"], from, to))
+	})
+
+	http.HandleFunc("/history/series", func(w http.ResponseWriter, r *http.Request) {
+		if samples == nil {
+			http.Error(w, "history is disabled, set HISTORY_PATH to enable it", http.StatusNotFound)
+			return
+		}
+		writeJSON(w, samples.Series())
+	})
+
+	// Add the versioned JSON API over the requested save
+	http.Handle(api.Prefix, api.NewHandler(requestSave))
+
+	// Add an alerts endpoint listing series matching the configured alert rules
+	http.HandleFunc("/alerts", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, evaluator.Firing())
+	})
+
+	// Add a parse diagnostics endpoint listing dropped and unknown properties
+	http.HandleFunc("/debug/parse", func(w http.ResponseWriter, r *http.Request) {
+		saveFile := requestSave(r)
+		if saveFile == nil {
+			http.Error(w, "no save file loaded yet", http.StatusServiceUnavailable)
+			return
+		}
+
+		diagnostics := saveFile.Diagnostics()
+		dropped := make(map[string]int)
+		unknown := make(map[string]int)
+		for _, diag := range diagnostics {
+			if diag.Dropped {
+				dropped[diag.Type]++
+			} else {
+				unknown[diag.Type]++
+			}
+		}
+		writeJSON(w, map[string]interface{}{
+			"dropped":     dropped,
+			"unknown":     unknown,
+			"diagnostics": diagnostics,
+		})
 	})
 
 	// Add a basic info endpoint
@@ -47,66 +358,103 @@ func main() {
 		<h1>Satisfactory Metrics Server</h1>
 		<hr>
 		<p><a href="/metrics">Prometheus Metrics</a></p>
+		<p><a href="/sessions">Sessions</a></p>
+		<p><a href="/collectibles">Collectibles</a></p>
+		<p><a href="/drop-pods">Drop Pods</a></p>
+		<p><a href="/players">Players</a></p>
+		<p><a href="/spatial">Spatial Query</a></p>
+		<p><a href="/api/v1/save">JSON API</a></p>
+		<p><a href="/archive">Save Archive</a></p>
+		<p><a href="/history/series">History</a></p>
+		<p><a href="/alerts">Alerts</a></p>
+		<p><a href="/debug/parse">Parse Diagnostics</a></p>
 		<p><a href="/health">Health Check</a></p>
+		<p><a href="/ready">Readiness Check</a></p>
 		`,
 		)
 	})
 
-	port := ":8081"
+	port := cfg.Listen
 	log.Printf("🚀 Starting HTTP server on %s", port)
 	log.Printf("📊 Prometheus metrics available at http://localhost%s/metrics", port)
 	log.Printf("💚 Health check available at http://localhost%s/health", port)
+	log.Printf("🟢 Readiness check available at http://localhost%s/ready", port)
 
 	if err := http.ListenAndServe(port, nil); err != nil {
 		log.Fatalf("Failed to start server: %v", err)
 	}
 }
 
-func loadAndServeMetrics(dirPath, jsonPath string) {
-	log.Printf("Loading save file from directory: %s", dirPath)
-	entries, err := os.ReadDir(dirPath)
-	if err != nil {
-		log.Fatalf("Error reading directory: %v", err)
-	}
+// backfillHistory records history for archived saves parsed before history
+// was enabled. Each save is parsed into a temporary directory removed after.
+func backfillHistory(samples *history.Store, store *archive.Store, parseOptions parser.Options) {
+	added, err := samples.Backfill(store, func(entry archive.Entry) (*savefile.SaveFile, error) {
+		path, err := store.Path(entry.Hash)
+		if err != nil {
+			return nil, err
+		}
+		jsonPath, err := os.MkdirTemp("", "satisfactory-buddy-backfill-*")
+		if err != nil {
+			return nil, fmt.Errorf("failed to create backfill directory: %w", err)
+		}
+		defer os.RemoveAll(jsonPath)
 
-	// Find the most recently modified save file
-	var latestFile os.DirEntry
-	var latestModTime time.Time
-	for _, entry := range entries {
-		log.Printf("Found file: %s", entry.Name())
-		if entry.Type().IsRegular() {
-			info, err := entry.Info()
-			if err != nil {
-				log.Printf("Error getting file info: %v", err)
-				continue
-			}
-			if info.ModTime().After(latestModTime) {
-				latestModTime = info.ModTime()
-				latestFile = entry
-			}
+		saveFile, err := parser.Parse(path, jsonPath, parseOptions)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse archived save %s: %w", entry.Hash, err)
 		}
+		return &saveFile, nil
+	})
+	if err != nil {
+		log.Printf("Warning: history backfill stopped: %v", err)
 	}
-	if latestFile == nil {
-		log.Println("No save files found")
-		return
-	}
+	log.Printf("Backfilled %d history samples from the archive", added)
+}
 
-	saveFilePath := dirPath + "/" + latestFile.Name()
-	saveFile, err := parser.Parse(saveFilePath, jsonPath)
-	log.Printf("✅ Successfully loaded save file: %s", saveFile.Header.SaveName)
-	log.Printf("📊 Save contains %d levels with buildings", len(saveFile.Levels))
+// requestSave returns the save selected by the request's server and session query values
+func requestSave(r *http.Request) *savefile.SaveFile {
+	query := r.URL.Query()
+	return getLatestSave(query.Get("server"), query.Get("session"))
+}
 
-	// Load save file
-	if err != nil {
-		log.Fatalf("Failed to decode save file: %v", err)
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("Error writing JSON response: %v", err)
 	}
+}
 
-	// Create metrics collector
-	collector := metrics.NewMetricsCollector(&saveFile)
+// parseVector parses map coordinates from query values, treating empty values as zero
+func parseVector(x, y, z string) (savefile.Vector3D, error) {
+	var vec savefile.Vector3D
+	for _, c := range []struct {
+		name  string
+		value string
+		dst   *float64
+	}{{"x", x, &vec.X}, {"y", y, &vec.Y}, {"z", z, &vec.Z}} {
+		if c.value == "" {
+			continue
+		}
+		f, err := strconv.ParseFloat(c.value, 64)
+		if err != nil || !api.IsFinite(f) {
+			return vec, fmt.Errorf("invalid %s coordinate %q", c.name, c.value)
+		}
+		*c.dst = f
+	}
+	return vec, nil
+}
 
-	// Initial metrics update
-	collector.UpdateMetrics()
-	// In a real implementation, you might reload the save file here
-	// For now, we'll just refresh the metrics from the same data
-	collector.UpdateMetrics()
+// parseTime parses an RFC 3339 time or Unix seconds, treating an empty value as the zero time
+func parseTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(seconds, 0), nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return t, fmt.Errorf("invalid time %q", value)
+	}
+	return t, nil
 }
@@ -0,0 +1,182 @@
+package alerts
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	dto "github.com/prometheus/client_model/go"
+)
+
+var (
+	// Alert metrics
+	alertsFiring = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "alerts_firing",
+			Help: "Number of series matching a configured alert rule",
+		},
+		[]string{"server", "session", "alert", "severity"},
+	)
+)
+
+// Rule fires for every series of Metric whose labels include Match and whose
+// value compares to Value with Op, e.g. power_consumption_mw > 1000
+type Rule struct {
+	Name     string            `yaml:"name" json:"name"`
+	Metric   string            `yaml:"metric" json:"metric"`
+	Match    map[string]string `yaml:"match" json:"match,omitempty"`
+	Op       string            `yaml:"op" json:"op"`
+	Value    float64           `yaml:"value" json:"value"`
+	Severity string            `yaml:"severity" json:"severity,omitempty"`
+}
+
+// Alert is one series matching a rule
+type Alert struct {
+	Rule     string            `json:"rule"`
+	Severity string            `json:"severity,omitempty"`
+	Labels   map[string]string `json:"labels"`
+	Value    float64           `json:"value"`
+}
+
+var comparisons = map[string]func(a, b float64) bool{
+	">":  func(a, b float64) bool { return a > b },
+	">=": func(a, b float64) bool { return a >= b },
+	"<":  func(a, b float64) bool { return a < b },
+	"<=": func(a, b float64) bool { return a <= b },
+	"==": func(a, b float64) bool { return a == b },
+	"!=": func(a, b float64) bool { return a != b },
+}
+
+// Validate reports missing fields and unknown operators
+func (r Rule) Validate() error {
+	var errs []error
+	if r.Name == "" {
+		errs = append(errs, errors.New("name is required"))
+	}
+	if r.Metric == "" {
+		errs = append(errs, errors.New("metric is required"))
+	}
+	if _, ok := comparisons[r.Op]; !ok {
+		errs = append(errs, fmt.Errorf("unknown op %q, want one of >, >=, <, <=, ==, !=", r.Op))
+	}
+	return errors.Join(errs...)
+}
+
+// Evaluator checks rules against gathered metrics
+type Evaluator struct {
+	mu     sync.RWMutex
+	rules  []Rule
+	firing []Alert
+	// emitted holds the alerts_firing series set by the last evaluation
+	emitted map[firingKey]bool
+}
+
+// firingKey is the label values of one alerts_firing series
+type firingKey struct {
+	server, session, alert, severity string
+}
+
+// SetRules replaces the rules checked by Evaluate
+func (e *Evaluator) SetRules(rules []Rule) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.rules = rules
+}
+
+// Firing returns the alerts found by the last evaluation
+func (e *Evaluator) Firing() []Alert {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.firing
+}
+
+// Evaluate checks every rule against the metrics from gatherer
+func (e *Evaluator) Evaluate(gatherer prometheus.Gatherer) error {
+	families, err := gatherer.Gather()
+	if err != nil {
+		return fmt.Errorf("failed to gather metrics: %w", err)
+	}
+	byName := make(map[string]*dto.MetricFamily, len(families))
+	for _, family := range families {
+		byName[family.GetName()] = family
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var firing []Alert
+	// Every rule reports a series per session it was checked against, or
+	// one without a session when its metric has no matching series, so a
+	// rule that stops firing drops to 0 rather than disappearing
+	counts := make(map[firingKey]float64)
+	for _, rule := range e.rules {
+		checked := false
+		compare := comparisons[rule.Op]
+		for _, metric := range byName[rule.Metric].GetMetric() {
+			labels := make(map[string]string, len(metric.Label))
+			for _, label := range metric.Label {
+				labels[label.GetName()] = label.GetValue()
+			}
+			if !matches(labels, rule.Match) {
+				continue
+			}
+			value, ok := metricValue(metric)
+			if !ok {
+				continue
+			}
+			checked = true
+			key := firingKey{labels["server"], labels["session"], rule.Name, rule.Severity}
+			count := counts[key]
+			if compare(value, rule.Value) {
+				firing = append(firing, Alert{Rule: rule.Name, Severity: rule.Severity, Labels: labels, Value: value})
+				count++
+			}
+			counts[key] = count
+		}
+		if !checked {
+			counts[firingKey{alert: rule.Name, severity: rule.Severity}] = 0
+		}
+	}
+
+	sort.SliceStable(firing, func(i, j int) bool {
+		return firing[i].Rule < firing[j].Rule
+	})
+	e.firing = firing
+
+	for key := range e.emitted {
+		if _, ok := counts[key]; !ok {
+			alertsFiring.DeleteLabelValues(key.server, key.session, key.alert, key.severity)
+		}
+	}
+	e.emitted = make(map[firingKey]bool, len(counts))
+	for key, count := range counts {
+		alertsFiring.WithLabelValues(key.server, key.session, key.alert, key.severity).Set(count)
+		e.emitted[key] = true
+	}
+	return nil
+}
+
+func matches(labels, match map[string]string) bool {
+	for name, value := range match {
+		if labels[name] != value {
+			return false
+		}
+	}
+	return true
+}
+
+func metricValue(metric *dto.Metric) (float64, bool) {
+	switch {
+	case metric.Gauge != nil:
+		return metric.Gauge.GetValue(), true
+	case metric.Counter != nil:
+		return metric.Counter.GetValue(), true
+	case metric.Untyped != nil:
+		return metric.Untyped.GetValue(), true
+	default:
+		return 0, false
+	}
+}
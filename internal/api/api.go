@@ -0,0 +1,229 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/FreekingDean/satisfactory-buddy/internal/savefile"
+)
+
+// Prefix is the path every version 1 endpoint is served under
+const Prefix = "/api/v1/"
+
+// Pagination limits for list endpoints
+const (
+	defaultLimit = 100
+	maxLimit     = 1000
+)
+
+// SaveLookup returns the save a request asks for, or nil when none is loaded
+type SaveLookup func(r *http.Request) *savefile.SaveFile
+
+// Page is one page of a list endpoint
+type Page struct {
+	Items  []interface{} `json:"items"`
+	Total  int           `json:"total"`
+	Offset int           `json:"offset"`
+	Limit  int           `json:"limit"`
+}
+
+// NewHandler returns the version 1 API. Every endpoint accepts ?server= and
+// ?session= to pick a save and ?fields= to return only some fields, where an
+// unknown field is a bad request; list endpoints also accept ?offset= and ?limit=.
+func NewHandler(lookup SaveLookup) http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("GET "+Prefix+"save", withSave(lookup, handleSave))
+	mux.Handle("GET "+Prefix+"objects", withSave(lookup, handleObjects))
+	mux.Handle("GET "+Prefix+"objects/{name...}", withSave(lookup, handleObject))
+	mux.Handle("GET "+Prefix+"circuits", withSave(lookup, handleCircuits))
+	mux.Handle("GET "+Prefix+"inventories", withSave(lookup, handleInventories))
+	mux.HandleFunc(Prefix, func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, "unknown endpoint %s", r.URL.Path)
+	})
+	return mux
+}
+
+// withSave resolves the requested save before calling handler
+func withSave(lookup SaveLookup, handler func(http.ResponseWriter, *http.Request, *savefile.SaveFile)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		saveFile := lookup(r)
+		if saveFile == nil {
+			writeError(w, http.StatusServiceUnavailable, "no save file loaded yet")
+			return
+		}
+		handler(w, r, saveFile)
+	})
+}
+
+// Save describes the loaded save
+type Save struct {
+	Header      savefile.Header `json:"header"`
+	Levels      int             `json:"levels"`
+	Objects     int             `json:"objects"`
+	Lightweight int             `json:"lightweightBuildables"`
+}
+
+func handleSave(w http.ResponseWriter, r *http.Request, saveFile *savefile.SaveFile) {
+	writeItem(w, r, Save{
+		Header:      saveFile.Header,
+		Levels:      len(saveFile.Levels),
+		Objects:     len(saveFile.AllGameObjects()),
+		Lightweight: len(saveFile.LightweightBuildables()),
+	})
+}
+
+// writeItem writes a single value, keeping only the requested fields
+func writeItem(w http.ResponseWriter, r *http.Request, item interface{}) {
+	fields := requestFields(r)
+	if err := checkFields(reflect.TypeOf(item), fields); err != nil {
+		writeError(w, http.StatusBadRequest, "%v", err)
+		return
+	}
+	selected, err := selectFields(item, fields)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to encode response: %v", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, selected)
+}
+
+// writePage writes the requested page of items, keeping only the requested fields
+func writePage[T any](w http.ResponseWriter, r *http.Request, items []T) {
+	offset, limit, err := pagination(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "%v", err)
+		return
+	}
+
+	fields := requestFields(r)
+	if err := checkFields(reflect.TypeOf((*T)(nil)).Elem(), fields); err != nil {
+		writeError(w, http.StatusBadRequest, "%v", err)
+		return
+	}
+
+	page := Page{Items: []interface{}{}, Total: len(items), Offset: offset, Limit: limit}
+	for i := offset; i < len(items) && i < offset+limit; i++ {
+		selected, err := selectFields(items[i], fields)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, "failed to encode response: %v", err)
+			return
+		}
+		page.Items = append(page.Items, selected)
+	}
+	writeJSON(w, http.StatusOK, page)
+}
+
+// pagination reads ?offset= and ?limit=
+func pagination(r *http.Request) (offset, limit int, err error) {
+	query := r.URL.Query()
+	limit = defaultLimit
+	if query.Has("offset") {
+		if offset, err = strconv.Atoi(query.Get("offset")); err != nil || offset < 0 {
+			return 0, 0, fmt.Errorf("invalid offset %q", query.Get("offset"))
+		}
+	}
+	if query.Has("limit") {
+		if limit, err = strconv.Atoi(query.Get("limit")); err != nil || limit <= 0 || limit > maxLimit {
+			return 0, 0, fmt.Errorf("invalid limit %q: want 1 to %d", query.Get("limit"), maxLimit)
+		}
+	}
+	return offset, limit, nil
+}
+
+// requestFields reads ?fields= as comma separated top level field names
+func requestFields(r *http.Request) []string {
+	var fields []string
+	for _, value := range r.URL.Query()["fields"] {
+		for _, field := range strings.Split(value, ",") {
+			if field = strings.TrimSpace(field); field != "" {
+				fields = append(fields, field)
+			}
+		}
+	}
+	return fields
+}
+
+// checkFields rejects requested fields that values of type t never have.
+// Fields are checked against the type rather than a value so fields left
+// out by omitempty are still accepted.
+func checkFields(t reflect.Type, fields []string) error {
+	known := jsonFields(t)
+	for _, field := range fields {
+		if !known[field] {
+			return fmt.Errorf("unknown field %q", field)
+		}
+	}
+	return nil
+}
+
+// jsonFields returns the top level JSON field names of a struct type,
+// including those of embedded structs
+func jsonFields(t reflect.Type) map[string]bool {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	fields := make(map[string]bool)
+	if t.Kind() != reflect.Struct {
+		return fields
+	}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		switch {
+		case name == "-":
+		case field.Anonymous && name == "":
+			for embedded := range jsonFields(field.Type) {
+				fields[embedded] = true
+			}
+		case !field.IsExported():
+		case name == "":
+			fields[field.Name] = true
+		default:
+			fields[name] = true
+		}
+	}
+	return fields
+}
+
+// selectFields returns item with only the named top level JSON fields, or
+// item itself when no fields were requested
+func selectFields(item interface{}, fields []string) (interface{}, error) {
+	if len(fields) == 0 {
+		return item, nil
+	}
+
+	data, err := json.Marshal(item)
+	if err != nil {
+		return nil, err
+	}
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, err
+	}
+
+	selected := make(map[string]json.RawMessage, len(fields))
+	for _, field := range fields {
+		if value, ok := all[field]; ok {
+			selected[field] = value
+		}
+	}
+	return selected, nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("Failed to write API response: %v", err)
+	}
+}
+
+// writeError writes {"error": "..."} so API clients can always decode the body
+func writeError(w http.ResponseWriter, status int, format string, args ...interface{}) {
+	writeJSON(w, status, map[string]string{"error": fmt.Sprintf(format, args...)})
+}
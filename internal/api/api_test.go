@@ -0,0 +1,196 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type testBase struct {
+	ID int `json:"id"`
+}
+
+type testItem struct {
+	testBase
+	Name    string `json:"name"`
+	Note    string `json:"note,omitempty"`
+	Ignored string `json:"-"`
+	Plain   float64
+	hidden  bool
+}
+
+func TestPagination(t *testing.T) {
+	tests := []struct {
+		query      string
+		wantOffset int
+		wantLimit  int
+		wantErr    bool
+	}{
+		{query: "", wantOffset: 0, wantLimit: defaultLimit},
+		{query: "offset=20&limit=10", wantOffset: 20, wantLimit: 10},
+		{query: "limit=1000", wantLimit: maxLimit},
+		{query: "offset=-1", wantErr: true},
+		{query: "offset=x", wantErr: true},
+		{query: "offset=", wantErr: true},
+		{query: "limit=0", wantErr: true},
+		{query: "limit=1001", wantErr: true},
+		{query: "limit=ten", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			offset, limit, err := pagination(httptest.NewRequest(http.MethodGet, "/?"+tt.query, nil))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("pagination() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && (offset != tt.wantOffset || limit != tt.wantLimit) {
+				t.Errorf("pagination() = %d, %d, want %d, %d", offset, limit, tt.wantOffset, tt.wantLimit)
+			}
+		})
+	}
+}
+
+func TestRequestFields(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{query: "", want: nil},
+		{query: "fields=id", want: []string{"id"}},
+		{query: "fields=id,%20name,,", want: []string{"id", "name"}},
+		{query: "fields=id&fields=note", want: []string{"id", "note"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			got := requestFields(httptest.NewRequest(http.MethodGet, "/?"+tt.query, nil))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("requestFields() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckFields(t *testing.T) {
+	tests := []struct {
+		name    string
+		fields  []string
+		wantErr string
+	}{
+		{name: "none", fields: nil},
+		{name: "tagged", fields: []string{"name"}},
+		{name: "embedded", fields: []string{"id"}},
+		{name: "omitempty", fields: []string{"note"}},
+		{name: "untagged", fields: []string{"Plain"}},
+		{name: "unknown", fields: []string{"name", "colour"}, wantErr: `unknown field "colour"`},
+		{name: "go name of tagged field", fields: []string{"Name"}, wantErr: `unknown field "Name"`},
+		{name: "ignored", fields: []string{"Ignored"}, wantErr: `unknown field "Ignored"`},
+		{name: "unexported", fields: []string{"hidden"}, wantErr: `unknown field "hidden"`},
+		{name: "embedded struct name", fields: []string{"testBase"}, wantErr: `unknown field "testBase"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, typ := range []reflect.Type{reflect.TypeOf(testItem{}), reflect.TypeOf(&testItem{})} {
+				err := checkFields(typ, tt.fields)
+				if tt.wantErr == "" && err != nil {
+					t.Errorf("checkFields(%s) error = %v", typ, err)
+				}
+				if tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr) {
+					t.Errorf("checkFields(%s) error = %v, want %q", typ, err, tt.wantErr)
+				}
+			}
+		})
+	}
+}
+
+func TestSelectFields(t *testing.T) {
+	item := testItem{testBase: testBase{ID: 7}, Name: "Smelter", Plain: 1.5}
+	tests := []struct {
+		name   string
+		fields []string
+		want   string
+	}{
+		{name: "all fields", fields: nil, want: `{"id":7,"name":"Smelter","Plain":1.5}`},
+		{name: "some fields", fields: []string{"name", "id"}, want: `{"id":7,"name":"Smelter"}`},
+		{name: "omitted field", fields: []string{"note"}, want: `{}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selected, err := selectFields(item, tt.fields)
+			if err != nil {
+				t.Fatalf("selectFields() error = %v", err)
+			}
+			got, err := json.Marshal(selected)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("selectFields() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWritePage(t *testing.T) {
+	items := make([]testItem, 5)
+	for i := range items {
+		items[i] = testItem{testBase: testBase{ID: i}, Name: "item"}
+	}
+
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		want       string
+	}{
+		{
+			name:       "default page",
+			query:      "fields=id",
+			wantStatus: http.StatusOK,
+			want:       `{"items":[{"id":0},{"id":1},{"id":2},{"id":3},{"id":4}],"total":5,"offset":0,"limit":100}`,
+		},
+		{
+			name:       "middle page",
+			query:      "offset=1&limit=2&fields=id",
+			wantStatus: http.StatusOK,
+			want:       `{"items":[{"id":1},{"id":2}],"total":5,"offset":1,"limit":2}`,
+		},
+		{
+			name:       "last partial page",
+			query:      "offset=4&limit=2&fields=id,name",
+			wantStatus: http.StatusOK,
+			want:       `{"items":[{"id":4,"name":"item"}],"total":5,"offset":4,"limit":2}`,
+		},
+		{
+			name:       "past the end",
+			query:      "offset=10",
+			wantStatus: http.StatusOK,
+			want:       `{"items":[],"total":5,"offset":10,"limit":100}`,
+		},
+		{
+			name:       "bad limit",
+			query:      "limit=0",
+			wantStatus: http.StatusBadRequest,
+			want:       `{"error":"invalid limit \"0\": want 1 to 1000"}`,
+		},
+		{
+			name:       "unknown field",
+			query:      "fields=id,colour",
+			wantStatus: http.StatusBadRequest,
+			want:       `{"error":"unknown field \"colour\""}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			writePage(w, httptest.NewRequest(http.MethodGet, "/?"+tt.query, nil), items)
+			if w.Code != tt.wantStatus {
+				t.Errorf("writePage() status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if got := strings.TrimSpace(w.Body.String()); got != tt.want {
+				t.Errorf("writePage() body = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
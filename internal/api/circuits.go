@@ -0,0 +1,76 @@
+package api
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/FreekingDean/satisfactory-buddy/internal/catalog"
+	"github.com/FreekingDean/satisfactory-buddy/internal/savefile"
+)
+
+// Circuit is a power circuit with the power of the buildings connected to it
+type Circuit struct {
+	ID            int     `json:"id"`
+	Name          string  `json:"name"`
+	Connections   int     `json:"connections"`
+	Buildings     int     `json:"buildings"`
+	GenerationMW  float64 `json:"generationMW"`
+	ConsumptionMW float64 `json:"consumptionMW"`
+	FuseTriggered bool    `json:"fuseTriggered"`
+}
+
+// handleCircuits lists every power circuit sorted by ID
+func handleCircuits(w http.ResponseWriter, r *http.Request, saveFile *savefile.SaveFile) {
+	byID := make(map[int]*Circuit)
+	var circuits []*Circuit
+	for _, obj := range saveFile.ObjectsOfType("FGPowerCircuit") {
+		id, err := obj.GetInt("mCircuitID")
+		if err != nil {
+			continue
+		}
+		circuit := &Circuit{ID: int(id), Name: obj.InstanceName}
+		circuit.FuseTriggered, _ = obj.GetBool("mIsFuseTriggered")
+
+		buildings := make(map[string]bool)
+		refs, _ := obj.GetObjectRefs("mComponents")
+		for _, ref := range refs {
+			circuit.Connections++
+			if connection := saveFile.GetGameObject(ref.PathName); connection != nil && connection.ParentEntityName != "" {
+				buildings[connection.ParentEntityName] = true
+			}
+		}
+		circuit.Buildings = len(buildings)
+
+		byID[circuit.ID] = circuit
+		circuits = append(circuits, circuit)
+	}
+
+	for _, info := range saveFile.ObjectsOfType("FGPowerInfoComponent") {
+		circuit, ok := byID[buildingCircuit(saveFile, info.ParentEntityName)]
+		if !ok {
+			continue
+		}
+		generation, consumption := catalog.PowerFlow(saveFile, info)
+		circuit.GenerationMW += generation
+		circuit.ConsumptionMW += consumption
+	}
+
+	sort.Slice(circuits, func(i, j int) bool {
+		return circuits[i].ID < circuits[j].ID
+	})
+	writePage(w, r, circuits)
+}
+
+// buildingCircuit returns the circuit a building's power connections are on, or -1
+func buildingCircuit(saveFile *savefile.SaveFile, name string) int {
+	building := saveFile.GetGameObject(name)
+	if building == nil {
+		return -1
+	}
+	for _, ref := range building.Components {
+		if circuit := saveFile.GetCircuit(ref.PathName); circuit != -1 {
+			return circuit
+		}
+	}
+	return -1
+}
@@ -0,0 +1,72 @@
+package api
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/FreekingDean/satisfactory-buddy/internal/catalog"
+	"github.com/FreekingDean/satisfactory-buddy/internal/savefile"
+)
+
+// Inventory is an inventory component and the items it holds by item class
+type Inventory struct {
+	Name       string         `json:"name"`
+	Owner      string         `json:"owner,omitempty"`
+	OwnerType  string         `json:"ownerType,omitempty"`
+	Stacks     int            `json:"stacks"`
+	UsedStacks int            `json:"usedStacks"`
+	Items      map[string]int `json:"items"`
+}
+
+// handleInventories lists every inventory, filtered by ?item= (repeatable
+// item class, matching inventories holding any of them) and ?type= (owner
+// type path or class name)
+func handleInventories(w http.ResponseWriter, r *http.Request, saveFile *savefile.SaveFile) {
+	query := r.URL.Query()
+	items := query["item"]
+	ownerType := query.Get("type")
+
+	inventories := []Inventory{}
+	for _, obj := range saveFile.AllGameObjects() {
+		// Stacks that fail to decode are left out rather than hiding the
+		// whole inventory
+		stacks, _ := savefile.StructArrayAs[savefile.InventoryStack](obj.Properties, "mInventoryStacks")
+		if stacks == nil {
+			continue
+		}
+
+		inventory := Inventory{Name: obj.InstanceName, Owner: obj.ParentEntityName, Stacks: len(stacks), Items: map[string]int{}}
+		owner := saveFile.GetGameObject(obj.ParentEntityName)
+		if owner != nil {
+			inventory.OwnerType = owner.SimpleType()
+		}
+		if ownerType != "" && (owner == nil || (owner.TypePath != ownerType && owner.SimpleType() != ownerType)) {
+			continue
+		}
+
+		for _, stack := range stacks {
+			if item := catalog.ClassName(stack.Item.ItemClass); item != "" && stack.NumItems > 0 {
+				inventory.Items[item] += stack.NumItems
+				inventory.UsedStacks++
+			}
+		}
+		if len(items) > 0 && !holdsAny(inventory, items) {
+			continue
+		}
+		inventories = append(inventories, inventory)
+	}
+
+	sort.Slice(inventories, func(i, j int) bool {
+		return inventories[i].Name < inventories[j].Name
+	})
+	writePage(w, r, inventories)
+}
+
+func holdsAny(inventory Inventory, items []string) bool {
+	for _, item := range items {
+		if inventory.Items[item] > 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,191 @@
+package api
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/FreekingDean/satisfactory-buddy/internal/savefile"
+)
+
+// Object is a game object as listed by /api/v1/objects
+type Object struct {
+	Name     string             `json:"name"`
+	Type     string             `json:"type"`
+	TypePath string             `json:"typePath"`
+	Level    string             `json:"level"`
+	Parent   string             `json:"parent,omitempty"`
+	Location *savefile.Vector3D `json:"location,omitempty"`
+	// Circuit is the power circuit of a power connection component
+	Circuit *int `json:"circuit,omitempty"`
+	// Distance is set when the objects were requested ?near= a point
+	Distance *float64 `json:"distance,omitempty"`
+}
+
+// ObjectDetail is a game object with its properties, components and references
+type ObjectDetail struct {
+	Object
+	Properties   savefile.PropertyContainer `json:"properties"`
+	Components   []Component                `json:"components"`
+	References   []savefile.Reference       `json:"references"`
+	ReferencedBy []savefile.Reference       `json:"referencedBy"`
+}
+
+// Component is a component of an object resolved to its properties, or
+// Missing when the save does not contain it
+type Component struct {
+	Object
+	Properties *savefile.PropertyContainer `json:"properties,omitempty"`
+	Missing    bool                        `json:"missing,omitempty"`
+}
+
+// handleObjects lists objects filtered by ?type= (repeatable, type path or
+// class name) and ?level=. With ?near=x,y,z objects are sorted by distance
+// and ?radius= drops those further away.
+func handleObjects(w http.ResponseWriter, r *http.Request, saveFile *savefile.SaveFile) {
+	query := r.URL.Query()
+
+	var near *savefile.Vector3D
+	if query.Has("near") {
+		point, err := parsePoint(query.Get("near"))
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "%v", err)
+			return
+		}
+		near = &point
+	}
+	var radius float64
+	if query.Has("radius") {
+		var err error
+		if radius, err = strconv.ParseFloat(query.Get("radius"), 64); err != nil || !IsFinite(radius) || radius < 0 || near == nil {
+			writeError(w, http.StatusBadRequest, "invalid radius %q: want a distance with near=x,y,z", query.Get("radius"))
+			return
+		}
+	}
+
+	types := query["type"]
+	var candidates []*savefile.GameObject
+	distances := make(map[string]float64)
+	switch {
+	case near != nil:
+		// The spatial index only holds located objects, leaving out
+		// components and subsystems that sit at the origin. Without a
+		// radius every entry is within an infinite one.
+		if !query.Has("radius") {
+			radius = math.Inf(1)
+		}
+		for _, entry := range saveFile.Within(*near, radius, types...) {
+			if entry.Object != nil {
+				candidates = append(candidates, entry.Object)
+				distances[entry.Name] = entry.Distance
+			}
+		}
+	case len(types) > 0:
+		seen := make(map[string]bool)
+		for _, t := range types {
+			for _, obj := range saveFile.ObjectsOfType(t) {
+				if !seen[obj.InstanceName] {
+					seen[obj.InstanceName] = true
+					candidates = append(candidates, obj)
+				}
+			}
+		}
+	default:
+		for _, obj := range saveFile.AllGameObjects() {
+			candidates = append(candidates, obj)
+		}
+	}
+
+	level := query.Get("level")
+	objects := make([]Object, 0, len(candidates))
+	for _, obj := range candidates {
+		if level != "" && saveFile.LevelOf(obj.InstanceName) != level {
+			continue
+		}
+		o := newObject(saveFile, obj)
+		if d, ok := distances[obj.InstanceName]; ok {
+			o.Distance = &d
+		}
+		objects = append(objects, o)
+	}
+
+	sort.Slice(objects, func(i, j int) bool {
+		if near != nil && *objects[i].Distance != *objects[j].Distance {
+			return *objects[i].Distance < *objects[j].Distance
+		}
+		return objects[i].Name < objects[j].Name
+	})
+	writePage(w, r, objects)
+}
+
+// handleObject returns one object by instance name with its components resolved
+func handleObject(w http.ResponseWriter, r *http.Request, saveFile *savefile.SaveFile) {
+	name := r.PathValue("name")
+	obj := saveFile.GetGameObject(name)
+	if obj == nil {
+		writeError(w, http.StatusNotFound, "object %q not found", name)
+		return
+	}
+
+	detail := ObjectDetail{
+		Object:       newObject(saveFile, obj),
+		Properties:   obj.Properties,
+		Components:   []Component{},
+		References:   append([]savefile.Reference{}, saveFile.References(name)...),
+		ReferencedBy: append([]savefile.Reference{}, saveFile.ReferencedBy(name)...),
+	}
+	for _, ref := range obj.Components {
+		component := saveFile.GetGameObject(ref.PathName)
+		if component == nil {
+			detail.Components = append(detail.Components, Component{Object: Object{Name: ref.PathName, Level: ref.LevelName}, Missing: true})
+			continue
+		}
+		detail.Components = append(detail.Components, Component{
+			Object:     newObject(saveFile, component),
+			Properties: &component.Properties,
+		})
+	}
+	writeItem(w, r, detail)
+}
+
+func newObject(saveFile *savefile.SaveFile, obj *savefile.GameObject) Object {
+	o := Object{
+		Name:     obj.InstanceName,
+		Type:     obj.SimpleType(),
+		TypePath: obj.TypePath,
+		Level:    saveFile.LevelOf(obj.InstanceName),
+		Parent:   obj.ParentEntityName,
+	}
+	if location := obj.Transform.Translation; location != (savefile.Vector3D{}) {
+		o.Location = &location
+	}
+	if circuit := saveFile.GetCircuit(obj.InstanceName); circuit != -1 {
+		o.Circuit = &circuit
+	}
+	return o
+}
+
+// parsePoint parses map coordinates written as x,y,z
+func parsePoint(value string) (savefile.Vector3D, error) {
+	var point savefile.Vector3D
+	parts := strings.Split(value, ",")
+	if len(parts) != 3 {
+		return point, fmt.Errorf("invalid point %q: want x,y,z", value)
+	}
+	for i, dst := range []*float64{&point.X, &point.Y, &point.Z} {
+		f, err := strconv.ParseFloat(strings.TrimSpace(parts[i]), 64)
+		if err != nil || !IsFinite(f) {
+			return point, fmt.Errorf("invalid point %q: want x,y,z", value)
+		}
+		*dst = f
+	}
+	return point, nil
+}
+
+// IsFinite rejects the NaN and infinities strconv.ParseFloat accepts
+func IsFinite(f float64) bool {
+	return !math.IsNaN(f) && !math.IsInf(f, 0)
+}
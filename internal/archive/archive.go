@@ -0,0 +1,278 @@
+package archive
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrNotFound is returned when no archived save has the requested hash
+var ErrNotFound = errors.New("archived save not found")
+
+// indexFile lists every archived save, relative to the store directory
+const indexFile = "index.json"
+
+// Entry describes one archived save
+type Entry struct {
+	Hash        string    `json:"hash"`
+	FileName    string    `json:"fileName"`
+	Server      string    `json:"server"`
+	SaveName    string    `json:"saveName"`
+	SessionName string    `json:"sessionName"`
+	SaveTime    time.Time `json:"saveTime"`
+	ArchivedAt  time.Time `json:"archivedAt"`
+	Size        int64     `json:"size"`
+	HasSummary  bool      `json:"hasSummary"`
+}
+
+// Store is a content-addressed archive of save files on local disk. Saves are
+// stored once per SHA-256 hash under objects/<first two hex digits>/<hash>.
+type Store struct {
+	dir    string
+	policy Policy
+
+	mu      sync.Mutex
+	entries map[string]Entry
+}
+
+// Open loads or creates an archive rooted at dir
+func Open(dir string, policy Policy) (*Store, error) {
+	if err := os.MkdirAll(filepath.Join(dir, "objects"), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create archive: %w", err)
+	}
+
+	store := &Store{dir: dir, policy: policy, entries: make(map[string]Entry)}
+	data, err := os.ReadFile(filepath.Join(dir, indexFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive index: %w", err)
+	}
+
+	var entries []Entry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to decode archive index: %w", err)
+	}
+	for _, entry := range entries {
+		store.entries[entry.Hash] = entry
+	}
+	return store, nil
+}
+
+// Add copies the save at path into the archive, along with summary when it is
+// not nil, then applies the retention policy. Saves already archived are not
+// copied again and report false.
+func (s *Store) Add(path string, entry Entry, summary interface{}) (Entry, bool, error) {
+	hash, size, err := hashFile(path)
+	if err != nil {
+		return Entry{}, false, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if existing, ok := s.entries[hash]; ok {
+		return existing, false, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.objectPath(hash)), 0o755); err != nil {
+		return Entry{}, false, fmt.Errorf("failed to create archive directory: %w", err)
+	}
+	if err := copyFile(path, s.objectPath(hash)); err != nil {
+		return Entry{}, false, err
+	}
+
+	entry.Hash = hash
+	entry.Size = size
+	entry.ArchivedAt = time.Now()
+	if entry.FileName == "" {
+		entry.FileName = filepath.Base(path)
+	}
+	if summary != nil {
+		data, err := json.Marshal(summary)
+		if err != nil {
+			return Entry{}, false, fmt.Errorf("failed to encode summary: %w", err)
+		}
+		if err := writeFileAtomic(s.summaryPath(hash), data); err != nil {
+			return Entry{}, false, err
+		}
+		entry.HasSummary = true
+	}
+
+	s.entries[hash] = entry
+	if err := s.prune(entry.ArchivedAt); err != nil {
+		return entry, true, err
+	}
+	return entry, true, s.writeIndex()
+}
+
+// List returns every archived save, oldest first
+func (s *Store) List() []Entry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sorted()
+}
+
+// Get returns the entry for an archived save
+func (s *Store) Get(hash string) (Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry, ok := s.entries[hash]
+	if !ok {
+		return Entry{}, fmt.Errorf("%s: %w", hash, ErrNotFound)
+	}
+	return entry, nil
+}
+
+// Open returns the contents of an archived save
+func (s *Store) Open(hash string) (io.ReadCloser, error) {
+	if _, err := s.Get(hash); err != nil {
+		return nil, err
+	}
+	return os.Open(s.objectPath(hash))
+}
+
+// Path returns the location of an archived save on disk
+func (s *Store) Path(hash string) (string, error) {
+	if _, err := s.Get(hash); err != nil {
+		return "", err
+	}
+	return s.objectPath(hash), nil
+}
+
+// Summary decodes the summary stored alongside an archived save into v
+func (s *Store) Summary(hash string, v interface{}) error {
+	entry, err := s.Get(hash)
+	if err != nil {
+		return err
+	}
+	if !entry.HasSummary {
+		return fmt.Errorf("%s: summary: %w", hash, ErrNotFound)
+	}
+	data, err := os.ReadFile(s.summaryPath(hash))
+	if err != nil {
+		return fmt.Errorf("failed to read summary: %w", err)
+	}
+	return json.Unmarshal(data, v)
+}
+
+// Prune removes saves no longer kept by the retention policy
+func (s *Store) Prune(now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.prune(now); err != nil {
+		return err
+	}
+	return s.writeIndex()
+}
+
+func (s *Store) prune(now time.Time) error {
+	keep := s.policy.Keep(s.sorted(), now)
+	for hash := range s.entries {
+		if keep[hash] {
+			continue
+		}
+		for _, path := range []string{s.objectPath(hash), s.summaryPath(hash)} {
+			if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return fmt.Errorf("failed to remove archived save: %w", err)
+			}
+		}
+		delete(s.entries, hash)
+	}
+	return nil
+}
+
+func (s *Store) sorted() []Entry {
+	entries := make([]Entry, 0, len(s.entries))
+	for _, entry := range s.entries {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].SaveTime.Equal(entries[j].SaveTime) {
+			return entries[i].Hash < entries[j].Hash
+		}
+		return entries[i].SaveTime.Before(entries[j].SaveTime)
+	})
+	return entries
+}
+
+func (s *Store) writeIndex() error {
+	data, err := json.MarshalIndent(s.sorted(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode archive index: %w", err)
+	}
+	return writeFileAtomic(filepath.Join(s.dir, indexFile), data)
+}
+
+func (s *Store) objectPath(hash string) string {
+	return filepath.Join(s.dir, "objects", hash[:2], hash)
+}
+
+func (s *Store) summaryPath(hash string) string {
+	return s.objectPath(hash) + ".summary.json"
+}
+
+func hashFile(path string) (string, int64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to open save: %w", err)
+	}
+	defer file.Close()
+
+	hasher := sha256.New()
+	size, err := io.Copy(hasher, file)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to hash save: %w", err)
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), size, nil
+}
+
+// copyFile copies src to dst through a temporary file so readers never see a partial save
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("failed to open save: %w", err)
+	}
+	defer in.Close()
+
+	tmp, err := os.CreateTemp(filepath.Dir(dst), ".archive-*")
+	if err != nil {
+		return fmt.Errorf("failed to create archived save: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, in); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to copy save: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to copy save: %w", err)
+	}
+	return os.Rename(tmp.Name(), dst)
+}
+
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".archive-*")
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+	}
+	return os.Rename(tmp.Name(), path)
+}
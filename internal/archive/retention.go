@@ -0,0 +1,117 @@
+package archive
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Rule keeps the newest save in every Every-long bucket for saves younger than For.
+// A zero For keeps the buckets forever.
+type Rule struct {
+	Every time.Duration
+	For   time.Duration
+}
+
+// Policy is a set of retention rules. A save is kept when any rule keeps it,
+// and the newest save of every world is always kept. Rules apply to each
+// world, a server and session pair, on its own so one world's saves never
+// prune another's.
+type Policy []Rule
+
+// DefaultPolicy keeps hourly saves for a day, daily for a month and weekly forever
+var DefaultPolicy = Policy{
+	{Every: time.Hour, For: 24 * time.Hour},
+	{Every: 24 * time.Hour, For: 30 * 24 * time.Hour},
+	{Every: 7 * 24 * time.Hour},
+}
+
+// world identifies the saves retention rules are applied to together
+type world struct {
+	server  string
+	session string
+}
+
+type bucketKey struct {
+	world
+	bucket int64
+}
+
+// Keep returns the hashes of the entries, sorted oldest first, the policy keeps at now
+func (p Policy) Keep(entries []Entry, now time.Time) map[string]bool {
+	keep := make(map[string]bool)
+
+	// Entries are sorted oldest first, so later ones replace earlier ones
+	newestOf := make(map[world]Entry)
+	for _, entry := range entries {
+		newestOf[world{entry.Server, entry.SessionName}] = entry
+	}
+	for _, entry := range newestOf {
+		keep[entry.Hash] = true
+	}
+
+	for _, rule := range p {
+		if rule.Every <= 0 {
+			continue
+		}
+		newest := make(map[bucketKey]Entry)
+		for _, entry := range entries {
+			if rule.For > 0 && now.Sub(entry.SaveTime) > rule.For {
+				continue
+			}
+			key := bucketKey{
+				world:  world{entry.Server, entry.SessionName},
+				bucket: entry.SaveTime.UnixNano() / int64(rule.Every),
+			}
+			newest[key] = entry
+		}
+		for _, entry := range newest {
+			keep[entry.Hash] = true
+		}
+	}
+	return keep
+}
+
+// ParsePolicy parses rules written as comma separated every:for pairs, such as
+// "1h:1d,1d:30d,1w:forever". Durations also accept d for days and w for weeks.
+func ParsePolicy(s string) (Policy, error) {
+	var policy Policy
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		every, keepFor, ok := strings.Cut(part, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid retention rule %q: want every:for", part)
+		}
+
+		var rule Rule
+		var err error
+		if rule.Every, err = parseDuration(every); err != nil || rule.Every <= 0 {
+			return nil, fmt.Errorf("invalid retention interval %q", every)
+		}
+		if keepFor != "forever" {
+			if rule.For, err = parseDuration(keepFor); err != nil || rule.For <= 0 {
+				return nil, fmt.Errorf("invalid retention period %q", keepFor)
+			}
+		}
+		policy = append(policy, rule)
+	}
+	return policy, nil
+}
+
+// parseDuration extends time.ParseDuration with d and w suffixes
+func parseDuration(s string) (time.Duration, error) {
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if n, ok := strings.CutSuffix(s, suffix); ok {
+			count, err := strconv.ParseFloat(n, 64)
+			if err != nil {
+				return 0, err
+			}
+			return time.Duration(count * float64(unit)), nil
+		}
+	}
+	return time.ParseDuration(s)
+}
@@ -0,0 +1,133 @@
+package archive
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestParsePolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    Policy
+		wantErr bool
+	}{
+		{name: "empty", input: "", want: nil},
+		{
+			name:  "default rules",
+			input: "1h:1d,1d:30d,1w:forever",
+			want: Policy{
+				{Every: time.Hour, For: 24 * time.Hour},
+				{Every: 24 * time.Hour, For: 30 * 24 * time.Hour},
+				{Every: 7 * 24 * time.Hour},
+			},
+		},
+		{name: "spaces and empty parts", input: " 30m:12h , ,", want: Policy{{Every: 30 * time.Minute, For: 12 * time.Hour}}},
+		{name: "fractional days", input: "0.5d:1.5w", want: Policy{{Every: 12 * time.Hour, For: 252 * time.Hour}}},
+		{name: "missing period", input: "1h", wantErr: true},
+		{name: "bad interval", input: "soon:1d", wantErr: true},
+		{name: "zero interval", input: "0h:1d", wantErr: true},
+		{name: "negative period", input: "1h:-1d", wantErr: true},
+		{name: "bad period", input: "1h:later", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePolicy(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParsePolicy(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParsePolicy(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPolicyKeep(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	entry := func(hash, server, session string, age time.Duration) Entry {
+		return Entry{Hash: hash, Server: server, SessionName: session, SaveTime: now.Add(-age)}
+	}
+
+	tests := []struct {
+		name    string
+		policy  Policy
+		entries []Entry
+		want    []string
+	}{
+		{name: "no entries", policy: DefaultPolicy, want: []string{}},
+		{
+			name:    "newest always kept",
+			policy:  nil,
+			entries: []Entry{entry("a", "s1", "w", 3*time.Hour), entry("b", "s1", "w", time.Hour)},
+			want:    []string{"b"},
+		},
+		{
+			name:   "newest per bucket",
+			policy: Policy{{Every: time.Hour, For: 24 * time.Hour}},
+			entries: []Entry{
+				entry("a", "s1", "w", 150*time.Minute),
+				entry("b", "s1", "w", 130*time.Minute),
+				entry("c", "s1", "w", 50*time.Minute),
+				entry("d", "s1", "w", 10*time.Minute),
+			},
+			want: []string{"b", "d"},
+		},
+		{
+			name:   "expired buckets dropped",
+			policy: Policy{{Every: time.Hour, For: 2 * time.Hour}},
+			entries: []Entry{
+				entry("a", "s1", "w", 5*time.Hour),
+				entry("b", "s1", "w", 90*time.Minute),
+				entry("c", "s1", "w", 10*time.Minute),
+			},
+			want: []string{"b", "c"},
+		},
+		{
+			name:   "forever rule",
+			policy: Policy{{Every: 24 * time.Hour}},
+			entries: []Entry{
+				entry("a", "s1", "w", 400*24*time.Hour),
+				entry("b", "s1", "w", 10*time.Minute),
+			},
+			want: []string{"a", "b"},
+		},
+		{
+			name:   "worlds pruned separately",
+			policy: Policy{{Every: time.Hour, For: 24 * time.Hour}},
+			entries: []Entry{
+				entry("a", "s1", "w", 30*time.Minute),
+				entry("b", "s2", "w", 20*time.Minute),
+				entry("c", "s1", "other", 15*time.Minute),
+				entry("d", "s1", "w", 10*time.Minute),
+			},
+			want: []string{"b", "c", "d"},
+		},
+		{
+			name:   "newest of every world kept",
+			policy: nil,
+			entries: []Entry{
+				entry("a", "s1", "w", 2*time.Hour),
+				entry("b", "s2", "w", time.Hour),
+			},
+			want: []string{"a", "b"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keep := tt.policy.Keep(tt.entries, now)
+			got := make([]string, 0, len(keep))
+			for hash, ok := range keep {
+				if ok {
+					got = append(got, hash)
+				}
+			}
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Keep() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
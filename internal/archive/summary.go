@@ -0,0 +1,45 @@
+package archive
+
+import (
+	"time"
+
+	"github.com/FreekingDean/satisfactory-buddy/internal/catalog"
+	"github.com/FreekingDean/satisfactory-buddy/internal/savefile"
+)
+
+// Summary is a compact description of a save kept next to it in the archive
+type Summary struct {
+	SaveName            string         `json:"saveName"`
+	SessionName         string         `json:"sessionName"`
+	SaveTime            time.Time      `json:"saveTime"`
+	PlayDurationSeconds int            `json:"playDurationSeconds"`
+	BuildVersion        int            `json:"buildVersion"`
+	Objects             int            `json:"objects"`
+	Lightweight         int            `json:"lightweight"`
+	Types               map[string]int `json:"types"`
+}
+
+// Summarize builds the archive summary and entry metadata for a parsed save
+func Summarize(sf *savefile.SaveFile) (Entry, Summary) {
+	types := make(map[string]int)
+	for typePath, count := range sf.TypeCounts() {
+		types[catalog.ClassName(typePath)] += count
+	}
+
+	summary := Summary{
+		SaveName:            sf.Header.SaveName,
+		SessionName:         sf.Header.SessionName,
+		SaveTime:            sf.Header.SaveDateTime.Time,
+		PlayDurationSeconds: sf.Header.PlayDurationSeconds,
+		BuildVersion:        sf.Header.BuildVersion,
+		Objects:             len(sf.AllGameObjects()),
+		Lightweight:         len(sf.LightweightBuildables()),
+		Types:               types,
+	}
+	entry := Entry{
+		SaveName:    sf.Header.SaveName,
+		SessionName: sf.Header.SessionName,
+		SaveTime:    sf.Header.SaveDateTime.Time,
+	}
+	return entry, summary
+}
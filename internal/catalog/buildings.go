@@ -0,0 +1,132 @@
+package catalog
+
+import (
+	"strings"
+)
+
+// Building categories used to group buildables
+const (
+	CategoryProduction = "production"
+	CategoryLogistics  = "logistics"
+	CategoryPower      = "power"
+	CategoryStructural = "structural"
+	CategoryDecor      = "decor"
+	CategoryOther      = "other"
+)
+
+var validCategories = map[string]bool{
+	CategoryProduction: true,
+	CategoryLogistics:  true,
+	CategoryPower:      true,
+	CategoryStructural: true,
+	CategoryDecor:      true,
+	CategoryOther:      true,
+}
+
+// BuildingCategories maps buildable classes that don't follow the naming
+// prefixes below to their category
+var BuildingCategories = map[string]string{
+	"Build_OilPump_C":             CategoryProduction,
+	"Build_WaterPump_C":           CategoryProduction,
+	"Build_ResourceSink_C":        CategoryProduction,
+	"Build_HadronCollider_C":      CategoryProduction,
+	"Build_QuantumEncoder_C":      CategoryProduction,
+	"Build_Converter_C":           CategoryProduction,
+	"Build_Packager_C":            CategoryProduction,
+	"Build_Blender_C":             CategoryProduction,
+	"Build_OilRefinery_C":         CategoryProduction,
+	"Build_CentralStorage_C":      CategoryLogistics,
+	"Build_IndustrialTank_C":      CategoryLogistics,
+	"Build_PipeStorageTank_C":     CategoryLogistics,
+	"Build_Valve_C":               CategoryLogistics,
+	"Build_TradingPost_C":         CategoryOther,
+	"Build_HubTerminal_C":         CategoryOther,
+	"Build_SpaceElevator_C":       CategoryOther,
+	"Build_Mam_C":                 CategoryOther,
+	"Build_AlienPowerBuilding_C":  CategoryPower,
+	"Build_PriorityPowerSwitch_C": CategoryPower,
+	"BUILD_Potty_mk1_C":           CategoryDecor,
+}
+
+// buildingPrefixes classifies buildables by the start of their class name.
+// Order matters: more specific prefixes come first.
+var buildingPrefixes = []struct {
+	prefix   string
+	category string
+}{
+	{"Build_Smelter", CategoryProduction},
+	{"Build_Constructor", CategoryProduction},
+	{"Build_Assembler", CategoryProduction},
+	{"Build_Manufacturer", CategoryProduction},
+	{"Build_Foundry", CategoryProduction},
+	{"Build_Miner", CategoryProduction},
+	{"Build_Fracking", CategoryProduction},
+	{"Build_WorkBench", CategoryProduction},
+	{"Build_Workshop", CategoryProduction},
+
+	{"Build_Generator", CategoryPower},
+	{"Build_Power", CategoryPower},
+
+	{"Build_Conveyor", CategoryLogistics},
+	{"Build_Pipeline", CategoryLogistics},
+	{"Build_PipeHyper", CategoryLogistics},
+	{"Build_HyperTube", CategoryLogistics},
+	{"Build_Railroad", CategoryLogistics},
+	{"Build_Train", CategoryLogistics},
+	{"Build_Storage", CategoryLogistics},
+	{"Build_DroneStation", CategoryLogistics},
+	{"Build_TruckStation", CategoryLogistics},
+	{"Build_FoundationPassthrough", CategoryLogistics},
+
+	{"Build_Foundation", CategoryStructural},
+	{"Build_Wall", CategoryStructural},
+	{"Build_Gate", CategoryStructural},
+	{"Build_Ramp", CategoryStructural},
+	{"Build_InvertedRamp", CategoryStructural},
+	{"Build_QuarterPipe", CategoryStructural},
+	{"Build_Roof", CategoryStructural},
+	{"Build_Stair", CategoryStructural},
+	{"Build_Stairs", CategoryStructural},
+	{"Build_Beam", CategoryStructural},
+	{"Build_Pillar", CategoryStructural},
+	{"Build_Frame", CategoryStructural},
+	{"Build_Fence", CategoryStructural},
+	{"Build_Railing", CategoryStructural},
+	{"Build_Walkway", CategoryStructural},
+	{"Build_Catwalk", CategoryStructural},
+	{"Build_Ladder", CategoryStructural},
+	{"Build_Barrier", CategoryStructural},
+	{"Build_Concrete", CategoryStructural},
+
+	{"Build_CeilingLight", CategoryDecor},
+	{"Build_StreetLight", CategoryDecor},
+	{"Build_FloodlightPole", CategoryDecor},
+	{"Build_FloodlightWall", CategoryDecor},
+	{"Build_LightsControlPanel", CategoryDecor},
+	{"Build_StandaloneWidgetSign", CategoryDecor},
+	{"Build_Sign", CategoryDecor},
+	{"Build_Snow", CategoryDecor},
+	{"Build_XMas", CategoryDecor},
+	{"Build_Potty", CategoryDecor},
+}
+
+// BuildingCategory returns the category for a buildable class
+func BuildingCategory(class string) string {
+	if category, ok := overrideBuildingCategory(class); ok {
+		return category
+	}
+	if category, ok := BuildingCategories[class]; ok {
+		return category
+	}
+	for _, p := range buildingPrefixes {
+		if strings.HasPrefix(class, p.prefix) {
+			return p.category
+		}
+	}
+	return CategoryOther
+}
+
+// IsBuildable reports whether a class is a player-built buildable
+func IsBuildable(class string) bool {
+	return strings.HasPrefix(strings.ToLower(class), "build_")
+}
@@ -0,0 +1,90 @@
+package catalog
+
+import (
+	"strings"
+)
+
+// Recipe describes the main product of a recipe at 100% clock speed
+type Recipe struct {
+	Product   string
+	PerMinute float64
+}
+
+// Recipes maps recipe classes to their main product and base rate
+var Recipes = map[string]Recipe{
+	"Recipe_SpaceElevatorPart_1_C":  {Product: "Desc_SpaceElevatorPart_1_C", PerMinute: 2},
+	"Recipe_SpaceElevatorPart_2_C":  {Product: "Desc_SpaceElevatorPart_2_C", PerMinute: 5},
+	"Recipe_SpaceElevatorPart_3_C":  {Product: "Desc_SpaceElevatorPart_3_C", PerMinute: 2.5},
+	"Recipe_SpaceElevatorPart_4_C":  {Product: "Desc_SpaceElevatorPart_4_C", PerMinute: 1},
+	"Recipe_SpaceElevatorPart_5_C":  {Product: "Desc_SpaceElevatorPart_5_C", PerMinute: 1},
+	"Recipe_SpaceElevatorPart_6_C":  {Product: "Desc_SpaceElevatorPart_6_C", PerMinute: 1},
+	"Recipe_SpaceElevatorPart_7_C":  {Product: "Desc_SpaceElevatorPart_7_C", PerMinute: 0.75},
+	"Recipe_SpaceElevatorPart_8_C":  {Product: "Desc_SpaceElevatorPart_8_C", PerMinute: 1},
+	"Recipe_SpaceElevatorPart_9_C":  {Product: "Desc_SpaceElevatorPart_9_C", PerMinute: 0.5},
+	"Recipe_SpaceElevatorPart_10_C": {Product: "Desc_SpaceElevatorPart_10_C", PerMinute: 2},
+	"Recipe_SpaceElevatorPart_11_C": {Product: "Desc_SpaceElevatorPart_11_C", PerMinute: 4},
+	"Recipe_SpaceElevatorPart_12_C": {Product: "Desc_SpaceElevatorPart_12_C", PerMinute: 1},
+}
+
+// ItemNames maps item descriptor classes to their in-game names
+var ItemNames = map[string]string{
+	"Desc_SpaceElevatorPart_1_C":  "Smart Plating",
+	"Desc_SpaceElevatorPart_2_C":  "Versatile Framework",
+	"Desc_SpaceElevatorPart_3_C":  "Automated Wiring",
+	"Desc_SpaceElevatorPart_4_C":  "Modular Engine",
+	"Desc_SpaceElevatorPart_5_C":  "Adaptive Control Unit",
+	"Desc_SpaceElevatorPart_6_C":  "Magnetic Field Generator",
+	"Desc_SpaceElevatorPart_7_C":  "Assembly Director System",
+	"Desc_SpaceElevatorPart_8_C":  "Thermal Propulsion Rocket",
+	"Desc_SpaceElevatorPart_9_C":  "Nuclear Pasta",
+	"Desc_SpaceElevatorPart_10_C": "Biochemical Sculptor",
+	"Desc_SpaceElevatorPart_11_C": "AI Expansion Server",
+	"Desc_SpaceElevatorPart_12_C": "Ballistic Warp Drive",
+}
+
+// ElevatorPhases lists the parts required to complete each space elevator phase
+var ElevatorPhases = map[int]map[string]int{
+	1: {
+		"Desc_SpaceElevatorPart_1_C": 50,
+	},
+	2: {
+		"Desc_SpaceElevatorPart_1_C": 1000,
+		"Desc_SpaceElevatorPart_2_C": 1000,
+		"Desc_SpaceElevatorPart_3_C": 100,
+	},
+	3: {
+		"Desc_SpaceElevatorPart_2_C": 2500,
+		"Desc_SpaceElevatorPart_4_C": 500,
+		"Desc_SpaceElevatorPart_5_C": 100,
+	},
+	4: {
+		"Desc_SpaceElevatorPart_7_C": 500,
+		"Desc_SpaceElevatorPart_6_C": 500,
+		"Desc_SpaceElevatorPart_8_C": 250,
+		"Desc_SpaceElevatorPart_9_C": 100,
+	},
+	5: {
+		"Desc_SpaceElevatorPart_9_C":  1000,
+		"Desc_SpaceElevatorPart_10_C": 1000,
+		"Desc_SpaceElevatorPart_11_C": 256,
+		"Desc_SpaceElevatorPart_12_C": 200,
+	},
+}
+
+// ClassName returns the class portion of an asset path such as
+// "/Game/FactoryGame/Recipes/Recipe_X.Recipe_X_C"
+func ClassName(path string) string {
+	parts := strings.Split(path, ".")
+	return parts[len(parts)-1]
+}
+
+// ItemName returns the in-game name for an item class, falling back to the class itself
+func ItemName(class string) string {
+	if name, ok := overrideItemName(class); ok {
+		return name
+	}
+	if name, ok := ItemNames[class]; ok {
+		return name
+	}
+	return class
+}
@@ -0,0 +1,73 @@
+package catalog
+
+// Stack sizes used by the game for item descriptors
+const (
+	StackSizeOne    = 1
+	StackSizeSmall  = 50
+	StackSizeMedium = 100
+	StackSizeBig    = 200
+	StackSizeHuge   = 500
+)
+
+// StackSizes maps item classes to their stack size. Items not listed use StackSizeMedium.
+var StackSizes = map[string]int{
+	"Desc_IronPlate_C":            StackSizeBig,
+	"Desc_IronRod_C":              StackSizeBig,
+	"Desc_IronPlateReinforced_C":  StackSizeMedium,
+	"Desc_Screw_C":                StackSizeHuge,
+	"Desc_Wire_C":                 StackSizeHuge,
+	"Desc_Cable_C":                StackSizeBig,
+	"Desc_Cement_C":               StackSizeHuge,
+	"Desc_HighSpeedWire_C":        StackSizeHuge,
+	"Desc_CopperSheet_C":          StackSizeBig,
+	"Desc_SteelPlate_C":           StackSizeBig,
+	"Desc_SteelPipe_C":            StackSizeBig,
+	"Desc_Rotor_C":                StackSizeMedium,
+	"Desc_Stator_C":               StackSizeMedium,
+	"Desc_Motor_C":                StackSizeSmall,
+	"Desc_ModularFrame_C":         StackSizeSmall,
+	"Desc_SteelPlateReinforced_C": StackSizeSmall,
+	"Desc_ModularFrameHeavy_C":    StackSizeSmall,
+	"Desc_ModularFrameFused_C":    StackSizeSmall,
+	"Desc_Computer_C":             StackSizeSmall,
+	"Desc_ComputerSuper_C":        StackSizeSmall,
+	"Desc_CircuitBoard_C":         StackSizeBig,
+	"Desc_Plastic_C":              StackSizeBig,
+	"Desc_Rubber_C":               StackSizeBig,
+	"Desc_Silica_C":               StackSizeBig,
+	"Desc_QuartzCrystal_C":        StackSizeBig,
+	"Desc_Biofuel_C":              StackSizeBig,
+	"Desc_Leaves_C":               StackSizeHuge,
+	"Desc_Wood_C":                 StackSizeBig,
+	"Desc_Mycelia_C":              StackSizeBig,
+	"Desc_GenericBiomass_C":       StackSizeBig,
+	"Desc_Crystal_C":              StackSizeSmall,
+	"Desc_Crystal_mk2_C":          StackSizeSmall,
+	"Desc_Crystal_mk3_C":          StackSizeSmall,
+	"Desc_HardDrive_C":            StackSizeOne,
+	"Desc_WAT1_C":                 StackSizeOne,
+	"Desc_WAT2_C":                 StackSizeOne,
+	"Desc_SpaceElevatorPart_1_C":  StackSizeSmall,
+	"Desc_SpaceElevatorPart_2_C":  StackSizeSmall,
+	"Desc_SpaceElevatorPart_3_C":  StackSizeSmall,
+	"Desc_SpaceElevatorPart_4_C":  StackSizeSmall,
+	"Desc_SpaceElevatorPart_5_C":  StackSizeSmall,
+	"Desc_SpaceElevatorPart_6_C":  StackSizeSmall,
+	"Desc_SpaceElevatorPart_7_C":  StackSizeSmall,
+	"Desc_SpaceElevatorPart_8_C":  StackSizeSmall,
+	"Desc_SpaceElevatorPart_9_C":  StackSizeSmall,
+	"Desc_SpaceElevatorPart_10_C": StackSizeSmall,
+	"Desc_SpaceElevatorPart_11_C": StackSizeSmall,
+	"Desc_SpaceElevatorPart_12_C": StackSizeSmall,
+}
+
+// StackSize returns the stack size for an item class
+func StackSize(class string) int {
+	if size, ok := overrideStackSize(class); ok {
+		return size
+	}
+	if size, ok := StackSizes[class]; ok {
+		return size
+	}
+	return StackSizeMedium
+}
@@ -0,0 +1,85 @@
+package catalog
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Overrides replaces or extends the built-in catalog, e.g. for modded items
+type Overrides struct {
+	ItemNames          map[string]string
+	StackSizes         map[string]int
+	BuildingCategories map[string]string
+	Recipes            map[string]Recipe
+}
+
+var (
+	overridesMu sync.RWMutex
+	overrides   Overrides
+)
+
+// ValidateOverrides checks overrides for unknown categories and invalid values
+func ValidateOverrides(o Overrides) error {
+	for class, category := range o.BuildingCategories {
+		if !validCategories[category] {
+			return fmt.Errorf("building %s: unknown category %q", class, category)
+		}
+	}
+	for class, size := range o.StackSizes {
+		if size <= 0 {
+			return fmt.Errorf("item %s: stack size must be positive", class)
+		}
+	}
+	for class, recipe := range o.Recipes {
+		if recipe.Product == "" || recipe.PerMinute <= 0 {
+			return fmt.Errorf("recipe %s: product and a positive rate are required", class)
+		}
+	}
+
+	return nil
+}
+
+// SetOverrides validates and replaces any previously set overrides
+func SetOverrides(o Overrides) error {
+	if err := ValidateOverrides(o); err != nil {
+		return err
+	}
+
+	overridesMu.Lock()
+	defer overridesMu.Unlock()
+	overrides = o
+	return nil
+}
+
+// RecipeFor returns the main product and base rate of a recipe class
+func RecipeFor(class string) (Recipe, bool) {
+	overridesMu.RLock()
+	recipe, ok := overrides.Recipes[class]
+	overridesMu.RUnlock()
+	if ok {
+		return recipe, true
+	}
+	recipe, ok = Recipes[class]
+	return recipe, ok
+}
+
+func overrideItemName(class string) (string, bool) {
+	overridesMu.RLock()
+	defer overridesMu.RUnlock()
+	name, ok := overrides.ItemNames[class]
+	return name, ok
+}
+
+func overrideStackSize(class string) (int, bool) {
+	overridesMu.RLock()
+	defer overridesMu.RUnlock()
+	size, ok := overrides.StackSizes[class]
+	return size, ok
+}
+
+func overrideBuildingCategory(class string) (string, bool) {
+	overridesMu.RLock()
+	defer overridesMu.RUnlock()
+	category, ok := overrides.BuildingCategories[class]
+	return category, ok
+}
@@ -0,0 +1,35 @@
+package catalog
+
+import (
+	"github.com/FreekingDean/satisfactory-buddy/internal/savefile"
+)
+
+// generatorClasses are the buildings whose FGPowerInfoComponent reports
+// generation. Every other building's reports consumption.
+var generatorClasses = map[string]bool{
+	"Build_GeneratorIntegratedBiomass_C": true,
+	"Build_GeneratorFuel_C":              true,
+}
+
+// IsGenerator reports whether the power info of a building class reports
+// generation rather than consumption
+func IsGenerator(class string) bool {
+	return generatorClasses[class]
+}
+
+// PowerFlow returns the generation and consumption in MW reported by an
+// FGPowerInfoComponent, classified by the building that owns it. Values the
+// save omits or holds with the wrong type count as 0, as does power info
+// whose building is missing.
+func PowerFlow(sf *savefile.SaveFile, info *savefile.GameObject) (generation, consumption float64) {
+	building := sf.GetGameObject(info.ParentEntityName)
+	if building == nil {
+		return 0, 0
+	}
+	if IsGenerator(building.SimpleType()) {
+		generation, _ = info.GetFloatOr("mDynamicProductionCapacity", 0)
+		return generation, 0
+	}
+	consumption, _ = info.GetFloatOr("mTargetConsumption", 0)
+	return 0, consumption
+}
@@ -0,0 +1,41 @@
+package catalog
+
+import (
+	"log"
+
+	"github.com/FreekingDean/satisfactory-buddy/internal/savefile"
+)
+
+// ProductionRates returns the per-minute output of the save's manufacturers
+// running a known recipe, keyed by item class such as "Desc_SpaceElevatorPart_1_C"
+func ProductionRates(sf *savefile.SaveFile) map[string]float64 {
+	rates := make(map[string]float64)
+	for _, obj := range sf.AllGameObjects() {
+		recipeRef, err := obj.GetObjectRef("mCurrentRecipe")
+		if err != nil || recipeRef.PathName == "" {
+			continue
+		}
+		recipe, ok := RecipeFor(ClassName(recipeRef.PathName))
+		if !ok {
+			continue
+		}
+		if paused, _ := obj.GetBool("mIsProductionPaused"); paused {
+			continue
+		}
+
+		potential, err := obj.GetFloatOr("mCurrentPotential", 1)
+		if err != nil {
+			log.Printf("Warning: %v", err)
+			continue
+		}
+		boost, err := obj.GetFloatOr("mCurrentProductionBoost", 1)
+		if err != nil {
+			log.Printf("Warning: %v", err)
+			continue
+		}
+
+		rates[recipe.Product] += recipe.PerMinute * potential * boost
+	}
+
+	return rates
+}
@@ -0,0 +1,162 @@
+package collectibles
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/FreekingDean/satisfactory-buddy/internal/savefile"
+)
+
+// Collectible kinds reported by the tracker
+const (
+	KindPowerSlugGreen  = "power_slug_green"
+	KindPowerSlugYellow = "power_slug_yellow"
+	KindPowerSlugPurple = "power_slug_purple"
+	// KindPowerSlug is a yellow or purple power slug whose instance name does
+	// not tell which, so it keeps one kind whether collected or not
+	KindPowerSlug    = "power_slug"
+	KindSomersloop   = "somersloop"
+	KindMercerSphere = "mercer_sphere"
+	KindHardDrive    = "hard_drive"
+	KindBerryBush    = "berry_bush"
+)
+
+// MapPoint is a static collectible location from the frontend's mappoints.json.
+// ClassName is actually the instance path, e.g. "PersistentLevel.BP_WAT10".
+type MapPoint struct {
+	X         float64 `json:"x"`
+	Y         float64 `json:"y"`
+	Z         float64 `json:"z"`
+	ClassName string  `json:"className"`
+	// Kind is the collectible kind, used only once the save no longer has the
+	// object to take its class from. Instance names are ambiguous, e.g.
+	// BP_WAT21 may be a somersloop or a mercer sphere, so it is stored here.
+	Kind string `json:"kind,omitempty"`
+}
+
+// classKinds maps the exact class of a collectible's save object to its kind
+var classKinds = map[string]string{
+	"BP_Crystal_C":     KindPowerSlugGreen,
+	"BP_Crystal_mk2_C": KindPowerSlugYellow,
+	"BP_Crystal_mk3_C": KindPowerSlugPurple,
+	"BP_WAT1_C":        KindSomersloop,
+	"BP_WAT2_C":        KindMercerSphere,
+	"BP_DropPod_C":     KindHardDrive,
+	"BP_BerryBush_C":   KindBerryBush,
+}
+
+// Collectible is a map point with its collection state in a save file
+type Collectible struct {
+	ID        string  `json:"id"`
+	Kind      string  `json:"kind"`
+	X         float64 `json:"x"`
+	Y         float64 `json:"y"`
+	Z         float64 `json:"z"`
+	Collected bool    `json:"collected"`
+}
+
+// Summary holds collected and remaining counts for one kind
+type Summary struct {
+	Total     int `json:"total"`
+	Collected int `json:"collected"`
+	Remaining int `json:"remaining"`
+}
+
+var (
+	pointsMu sync.RWMutex
+	points   []MapPoint
+)
+
+// LoadMapPoints reads the static map points used to diff against save files
+func LoadMapPoints(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open map points: %w", err)
+	}
+	defer file.Close()
+
+	var loaded []MapPoint
+	if err := json.NewDecoder(file).Decode(&loaded); err != nil {
+		return fmt.Errorf("failed to decode map points: %w", err)
+	}
+
+	pointsMu.Lock()
+	defer pointsMu.Unlock()
+	points = loaded
+	return nil
+}
+
+// Track diffs the static map points against the save and returns every
+// known collectible with its collected state
+func Track(sf *savefile.SaveFile) []Collectible {
+	pointsMu.RLock()
+	defer pointsMu.RUnlock()
+
+	objects := make(map[string]*savefile.GameObject)
+	for _, obj := range sf.AllGameObjects() {
+		objects[savefile.LevelPath(obj.InstanceName)] = obj
+	}
+
+	var result []Collectible
+	for _, point := range points {
+		obj := objects[point.ClassName]
+		kind := pointKind(point, obj)
+		if kind == "" {
+			continue
+		}
+
+		result = append(result, Collectible{
+			ID:        point.ClassName,
+			Kind:      kind,
+			X:         point.X,
+			Y:         point.Y,
+			Z:         point.Z,
+			Collected: isCollected(sf, point.ClassName, obj),
+		})
+	}
+
+	return result
+}
+
+// Summarize counts collected and remaining collectibles by kind
+func Summarize(items []Collectible) map[string]Summary {
+	summaries := make(map[string]Summary)
+	for _, item := range items {
+		summary := summaries[item.Kind]
+		summary.Total++
+		if item.Collected {
+			summary.Collected++
+		} else {
+			summary.Remaining++
+		}
+		summaries[item.Kind] = summary
+	}
+	return summaries
+}
+
+func isCollected(sf *savefile.SaveFile, path string, obj *savefile.GameObject) bool {
+	if sf.IsDestroyed(path) {
+		return true
+	}
+	if obj == nil {
+		return false
+	}
+	for _, name := range []string{"mHasBeenOpened", "mIsLooted", "mPickedUp"} {
+		if collected, _ := obj.GetBool(name); collected {
+			return true
+		}
+	}
+	return false
+}
+
+// pointKind classifies a map point by the exact class of the matching save
+// object, falling back to its stored kind once the object is gone after being
+// collected. Points with neither are skipped.
+func pointKind(point MapPoint, obj *savefile.GameObject) string {
+	if obj != nil {
+		return classKinds[obj.SimpleType()]
+	}
+	return point.Kind
+}
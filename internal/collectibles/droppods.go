@@ -0,0 +1,109 @@
+package collectibles
+
+import (
+	"sort"
+
+	"github.com/FreekingDean/satisfactory-buddy/internal/catalog"
+	"github.com/FreekingDean/satisfactory-buddy/internal/savefile"
+)
+
+// debrisRadius is how far from a drop pod crash site debris is attributed to it
+const debrisRadius = 10000.0
+
+// DropPod describes a crash site and what it takes to open it
+type DropPod struct {
+	ID          string       `json:"id"`
+	X           float64      `json:"x"`
+	Y           float64      `json:"y"`
+	Z           float64      `json:"z"`
+	Opened      bool         `json:"opened"`
+	Debris      int          `json:"debris"`
+	Distance    float64      `json:"distance"`
+	Requirement *Requirement `json:"requirement,omitempty"`
+}
+
+// Requirement is the power or item cost needed to open a drop pod
+type Requirement struct {
+	PowerMW  float64 `json:"powerMW,omitempty"`
+	Item     string  `json:"item,omitempty"`
+	ItemName string  `json:"itemName,omitempty"`
+	Amount   int     `json:"amount,omitempty"`
+}
+
+// DropPods reports every drop pod sorted by distance from origin
+func DropPods(sf *savefile.SaveFile, origin savefile.Vector3D) []DropPod {
+	pods := make(map[string]*DropPod)
+
+	for _, obj := range sf.ObjectsOfType("BP_DropPod_C") {
+		id := savefile.LevelPath(obj.InstanceName)
+		pods[id] = &DropPod{
+			ID:          id,
+			X:           obj.Transform.Translation.X,
+			Y:           obj.Transform.Translation.Y,
+			Z:           obj.Transform.Translation.Z,
+			Opened:      isCollected(sf, id, obj),
+			Requirement: dropPodRequirement(obj),
+		}
+	}
+
+	// Pods streamed out of the save are still known from the static map points
+	pointsMu.RLock()
+	for _, point := range points {
+		if _, ok := pods[point.ClassName]; ok || pointKind(point, nil) != KindHardDrive {
+			continue
+		}
+		pods[point.ClassName] = &DropPod{
+			ID:     point.ClassName,
+			X:      point.X,
+			Y:      point.Y,
+			Z:      point.Z,
+			Opened: sf.IsDestroyed(point.ClassName),
+		}
+	}
+	pointsMu.RUnlock()
+
+	result := make([]DropPod, 0, len(pods))
+	for _, pod := range pods {
+		location := savefile.Vector3D{X: pod.X, Y: pod.Y, Z: pod.Z}
+		pod.Debris = len(sf.Within(location, debrisRadius, "BP_CrashSiteDebris_C"))
+		pod.Distance = savefile.Distance(location, origin)
+		result = append(result, *pod)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Distance == result[j].Distance {
+			return result[i].ID < result[j].ID
+		}
+		return result[i].Distance < result[j].Distance
+	})
+
+	return result
+}
+
+// HubLocation returns the location of the HUB terminal, if one has been built
+func HubLocation(sf *savefile.SaveFile) (savefile.Vector3D, bool) {
+	if hubs := sf.ObjectsOfType("Build_HubTerminal_C"); len(hubs) > 0 {
+		return hubs[0].Transform.Translation, true
+	}
+	return savefile.Vector3D{}, false
+}
+
+func dropPodRequirement(obj *savefile.GameObject) *Requirement {
+	for _, name := range []string{"mPowerConsumption", "mRequiredPower"} {
+		if power, err := obj.GetFloat(name); err == nil && power > 0 {
+			return &Requirement{PowerMW: power}
+		}
+	}
+
+	cost, err := savefile.StructAs[savefile.ItemAmount](obj.Properties, "mUnlockCost")
+	if err != nil || cost.ItemClass == "" {
+		return nil
+	}
+
+	item := catalog.ClassName(cost.ItemClass)
+	return &Requirement{
+		Item:     item,
+		ItemName: catalog.ItemName(item),
+		Amount:   cost.Amount,
+	}
+}
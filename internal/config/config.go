@@ -0,0 +1,231 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/FreekingDean/satisfactory-buddy/internal/alerts"
+	"github.com/FreekingDean/satisfactory-buddy/internal/archive"
+	"github.com/FreekingDean/satisfactory-buddy/internal/catalog"
+	"github.com/FreekingDean/satisfactory-buddy/internal/metrics"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultServer names the save source when only SAVES_DIR is configured
+const DefaultServer = "default"
+
+// Config is the complete exporter configuration
+type Config struct {
+	// Listen is the HTTP listen address
+	Listen string `yaml:"listen"`
+	// Interval is how often every source is checked for new saves
+	Interval time.Duration `yaml:"interval"`
+	// JSONDir is where the parser writes its JSON output
+	JSONDir string `yaml:"jsonDir"`
+	// MapPoints is the static collectible locations file
+	MapPoints string `yaml:"mapPoints"`
+	// Strict fails a parse when any property could not be decoded or has an
+	// unknown type
+	Strict bool `yaml:"strict"`
+	// SaveTimestamps stamps samples with the save's own timestamp
+	SaveTimestamps bool `yaml:"saveTimestamps"`
+
+	Sources    []Source      `yaml:"sources"`
+	Collectors []string      `yaml:"collectors"`
+	Labels     Labels        `yaml:"labels"`
+	Archive    Archive       `yaml:"archive"`
+	History    History       `yaml:"history"`
+	Catalog    Catalog       `yaml:"catalog"`
+	Alerts     []alerts.Rule `yaml:"alerts"`
+}
+
+// Source is a directory of saves written by one server
+type Source struct {
+	Server string `yaml:"server"`
+	Dir    string `yaml:"dir"`
+}
+
+// Labels configures optional metric labels
+type Labels struct {
+	// RegionGridSize splits the map into square regions of this many meters
+	// for the buildings_total region label. Zero disables regions.
+	RegionGridSize float64 `yaml:"regionGridSize"`
+}
+
+// Archive configures the save archive, disabled when Dir is empty
+type Archive struct {
+	Dir string `yaml:"dir"`
+	// Retention is a policy such as "1h:1d,1d:30d,1w:forever"
+	Retention string `yaml:"retention"`
+	Summary   bool   `yaml:"summary"`
+}
+
+// History configures the derived value history, disabled when Path is empty
+type History struct {
+	Path     string `yaml:"path"`
+	Backfill bool   `yaml:"backfill"`
+}
+
+// Catalog overrides item names, stack sizes, building categories and recipes
+type Catalog struct {
+	ItemNames          map[string]string `yaml:"itemNames"`
+	StackSizes         map[string]int    `yaml:"stackSizes"`
+	BuildingCategories map[string]string `yaml:"buildingCategories"`
+	Recipes            map[string]Recipe `yaml:"recipes"`
+}
+
+// Recipe is the main product of a recipe at 100% clock speed
+type Recipe struct {
+	Product   string  `yaml:"product"`
+	PerMinute float64 `yaml:"perMinute"`
+}
+
+// Default returns the configuration used when nothing is set
+func Default() Config {
+	return Config{
+		Listen:    ":8081",
+		Interval:  30 * time.Second,
+		MapPoints: "./frontend/src/data/mappoints.json",
+		Archive:   Archive{Summary: true},
+	}
+}
+
+// Load reads the YAML file at path, if any, over the defaults and then
+// applies environment variable overrides. The result is not validated.
+func Load(path string) (Config, error) {
+	cfg := Default()
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return cfg, fmt.Errorf("failed to read config: %w", err)
+		}
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(&cfg); err != nil && !errors.Is(err, io.EOF) {
+			return cfg, fmt.Errorf("failed to parse config %s: %w", path, err)
+		}
+	}
+
+	if err := cfg.applyEnv(); err != nil {
+		return cfg, err
+	}
+	return cfg, nil
+}
+
+// Validate checks the whole configuration and reports every problem found
+func (c Config) Validate() error {
+	var errs []error
+	fail := func(format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	if c.Listen == "" {
+		fail("listen: address is required")
+	}
+	if c.Interval < time.Second {
+		fail("interval: must be at least 1s, got %s", c.Interval)
+	}
+
+	if len(c.Sources) == 0 {
+		fail("sources: at least one save source is required, set SAVES_DIR or sources in the config file")
+	}
+	// Directories are not checked for existence: a missing or not yet
+	// mounted directory is retried by its loader and reported on /health
+	servers := make(map[string]bool)
+	dirs := make(map[string]bool)
+	for i, src := range c.Sources {
+		switch {
+		case src.Server == "":
+			fail("sources[%d]: server is required", i)
+		case servers[src.Server]:
+			fail("sources[%d]: duplicate server %q", i, src.Server)
+		}
+		servers[src.Server] = true
+
+		switch {
+		case src.Dir == "":
+			fail("sources[%d]: dir is required", i)
+		case filepath.Clean(src.Dir) != src.Dir:
+			fail("sources[%d]: dir %q is not a clean path, use %q", i, src.Dir, filepath.Clean(src.Dir))
+		case dirs[src.Dir]:
+			fail("sources[%d]: duplicate dir %q", i, src.Dir)
+		}
+		dirs[src.Dir] = true
+	}
+
+	known := make(map[string]bool)
+	for _, name := range metrics.CollectorNames() {
+		known[name] = true
+	}
+	for _, name := range c.Collectors {
+		if !known[name] {
+			fail("collectors: unknown collector %q, want one of %v", name, metrics.CollectorNames())
+		}
+	}
+
+	if c.Labels.RegionGridSize < 0 {
+		fail("labels.regionGridSize: must not be negative")
+	}
+
+	if c.Archive.Retention != "" {
+		if _, err := archive.ParsePolicy(c.Archive.Retention); err != nil {
+			fail("archive.retention: %v", err)
+		}
+	}
+	if c.History.Backfill && c.Archive.Dir == "" {
+		fail("history.backfill: requires archive.dir")
+	}
+
+	if err := catalog.ValidateOverrides(c.CatalogOverrides()); err != nil {
+		fail("catalog: %v", err)
+	}
+
+	rules := make(map[string]bool)
+	for i, rule := range c.Alerts {
+		if err := rule.Validate(); err != nil {
+			fail("alerts[%d]: %v", i, err)
+		}
+		if rules[rule.Name] {
+			fail("alerts[%d]: duplicate rule %q", i, rule.Name)
+		}
+		rules[rule.Name] = true
+	}
+
+	return errors.Join(errs...)
+}
+
+// RetentionPolicy returns the archive retention policy, or the default when unset
+func (c Config) RetentionPolicy() archive.Policy {
+	if c.Archive.Retention == "" {
+		return archive.DefaultPolicy
+	}
+	policy, _ := archive.ParsePolicy(c.Archive.Retention)
+	return policy
+}
+
+// MetricsOptions returns the collector options for the configuration
+func (c Config) MetricsOptions() metrics.Options {
+	return metrics.Options{
+		RegionGridSize: c.Labels.RegionGridSize,
+		Collectors:     c.Collectors,
+	}
+}
+
+// CatalogOverrides returns the catalog overrides for the configuration
+func (c Config) CatalogOverrides() catalog.Overrides {
+	recipes := make(map[string]catalog.Recipe, len(c.Catalog.Recipes))
+	for class, recipe := range c.Catalog.Recipes {
+		recipes[class] = catalog.Recipe{Product: recipe.Product, PerMinute: recipe.PerMinute}
+	}
+	return catalog.Overrides{
+		ItemNames:          c.Catalog.ItemNames,
+		StackSizes:         c.Catalog.StackSizes,
+		BuildingCategories: c.Catalog.BuildingCategories,
+		Recipes:            recipes,
+	}
+}
@@ -0,0 +1,267 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/FreekingDean/satisfactory-buddy/internal/alerts"
+	"github.com/FreekingDean/satisfactory-buddy/internal/metrics"
+)
+
+func TestValidate(t *testing.T) {
+	dir := t.TempDir()
+	valid := func() Config {
+		cfg := Default()
+		cfg.Sources = []Source{{Server: "s1", Dir: dir}}
+		return cfg
+	}
+	rule := alerts.Rule{Name: "power", Metric: "power_consumption_mw", Op: ">", Value: 1000}
+
+	tests := []struct {
+		name   string
+		modify func(*Config)
+		want   []string
+	}{
+		{name: "defaults with a source", modify: func(*Config) {}},
+		{
+			name: "everything set",
+			modify: func(c *Config) {
+				c.Sources = append(c.Sources, Source{Server: "s2", Dir: filepath.Join(dir, "s2")})
+				c.Collectors = metrics.CollectorNames()[:1]
+				c.Labels.RegionGridSize = 500
+				c.Archive = Archive{Dir: dir, Retention: "1h:1d,1d:forever"}
+				c.History = History{Path: filepath.Join(dir, "history.jsonl"), Backfill: true}
+				c.Catalog.StackSizes = map[string]int{"Desc_Screw_C": 500}
+				c.Alerts = []alerts.Rule{rule}
+			},
+		},
+		{name: "missing listen", modify: func(c *Config) { c.Listen = "" }, want: []string{"listen: address is required"}},
+		{name: "short interval", modify: func(c *Config) { c.Interval = 500 * time.Millisecond }, want: []string{"interval: must be at least 1s"}},
+		{name: "no sources", modify: func(c *Config) { c.Sources = nil }, want: []string{"sources: at least one save source is required"}},
+		{
+			name:   "source fields required",
+			modify: func(c *Config) { c.Sources = []Source{{}} },
+			want:   []string{"sources[0]: server is required", "sources[0]: dir is required"},
+		},
+		{
+			name:   "duplicate server",
+			modify: func(c *Config) { c.Sources = append(c.Sources, Source{Server: "s1", Dir: filepath.Join(dir, "s2")}) },
+			want:   []string{`sources[1]: duplicate server "s1"`},
+		},
+		{
+			name:   "missing dir is left to the loader",
+			modify: func(c *Config) { c.Sources[0].Dir = filepath.Join(dir, "not", "mounted") },
+		},
+		{
+			name:   "relative dir",
+			modify: func(c *Config) { c.Sources[0].Dir = "saves" },
+		},
+		{
+			name:   "unclean dir",
+			modify: func(c *Config) { c.Sources[0].Dir = dir + "/saves/../server/" },
+			want:   []string{`sources[0]: dir "` + dir + `/saves/../server/" is not a clean path, use "` + dir + `/server"`},
+		},
+		{
+			name:   "duplicate dir",
+			modify: func(c *Config) { c.Sources = append(c.Sources, Source{Server: "s2", Dir: dir}) },
+			want:   []string{`sources[1]: duplicate dir "` + dir + `"`},
+		},
+		{
+			name:   "unknown collector",
+			modify: func(c *Config) { c.Collectors = []string{"nope"} },
+			want:   []string{`collectors: unknown collector "nope"`},
+		},
+		{
+			name:   "negative region grid",
+			modify: func(c *Config) { c.Labels.RegionGridSize = -1 },
+			want:   []string{"labels.regionGridSize: must not be negative"},
+		},
+		{
+			name:   "bad retention",
+			modify: func(c *Config) { c.Archive.Retention = "soon" },
+			want:   []string{"archive.retention:"},
+		},
+		{
+			name:   "backfill without archive",
+			modify: func(c *Config) { c.History.Backfill = true },
+			want:   []string{"history.backfill: requires archive.dir"},
+		},
+		{
+			name:   "bad catalog override",
+			modify: func(c *Config) { c.Catalog.StackSizes = map[string]int{"Desc_Screw_C": 0} },
+			want:   []string{"catalog: item Desc_Screw_C: stack size must be positive"},
+		},
+		{
+			name: "bad and duplicate alerts",
+			modify: func(c *Config) {
+				c.Alerts = []alerts.Rule{rule, rule, {Name: "broken", Metric: "x", Op: "~"}}
+			},
+			want: []string{`alerts[1]: duplicate rule "power"`, `alerts[2]: unknown op "~"`},
+		},
+		{
+			name: "every problem reported",
+			modify: func(c *Config) {
+				c.Listen = ""
+				c.Interval = 0
+				c.Sources = nil
+			},
+			want: []string{"listen:", "interval:", "sources:"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := valid()
+			tt.modify(&cfg)
+			err := cfg.Validate()
+			if len(tt.want) == 0 {
+				if err != nil {
+					t.Fatalf("Validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("Validate() succeeded, want errors %q", tt.want)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("Validate() error = %v, want it to contain %q", err, want)
+				}
+			}
+		})
+	}
+}
+
+// envNames lists every variable applyEnv reads
+var envNames = []string{
+	"LISTEN_ADDR", "JSON_DIR", "MAP_POINTS_PATH", "ARCHIVE_DIR", "ARCHIVE_RETENTION", "HISTORY_PATH",
+	"STRICT_PARSE", "SAVE_TIMESTAMPS", "ARCHIVE_SUMMARY", "HISTORY_BACKFILL",
+	"PARSE_INTERVAL", "REGION_GRID_SIZE", "COLLECTORS", "SAVE_SOURCES", "SAVES_DIR", "SERVER_NAME",
+}
+
+func TestApplyEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		want    func(*Config)
+		wantErr string
+	}{
+		{name: "nothing set", want: func(*Config) {}},
+		{
+			name: "strings",
+			env: map[string]string{
+				"LISTEN_ADDR": ":9000", "JSON_DIR": "/json", "MAP_POINTS_PATH": "/points.json",
+				"ARCHIVE_DIR": "/archive", "ARCHIVE_RETENTION": "1h:forever", "HISTORY_PATH": "/history.jsonl",
+			},
+			want: func(c *Config) {
+				c.Listen = ":9000"
+				c.JSONDir = "/json"
+				c.MapPoints = "/points.json"
+				c.Archive.Dir = "/archive"
+				c.Archive.Retention = "1h:forever"
+				c.History.Path = "/history.jsonl"
+			},
+		},
+		{
+			name: "empty string clears",
+			env:  map[string]string{"MAP_POINTS_PATH": ""},
+			want: func(c *Config) { c.MapPoints = "" },
+		},
+		{
+			name: "bools",
+			env: map[string]string{
+				"STRICT_PARSE": "true", "SAVE_TIMESTAMPS": "1", "ARCHIVE_SUMMARY": "false", "HISTORY_BACKFILL": "TRUE",
+			},
+			want: func(c *Config) {
+				c.Strict = true
+				c.SaveTimestamps = true
+				c.Archive.Summary = false
+				c.History.Backfill = true
+			},
+		},
+		{
+			name: "empty bool keeps default",
+			env:  map[string]string{"ARCHIVE_SUMMARY": ""},
+			want: func(*Config) {},
+		},
+		{
+			name:    "invalid bool",
+			env:     map[string]string{"STRICT_PARSE": "yes"},
+			wantErr: `invalid STRICT_PARSE "yes": want true or false`,
+		},
+		{
+			name: "interval and grid",
+			env:  map[string]string{"PARSE_INTERVAL": "5m", "REGION_GRID_SIZE": "250.5"},
+			want: func(c *Config) {
+				c.Interval = 5 * time.Minute
+				c.Labels.RegionGridSize = 250.5
+			},
+		},
+		{
+			name:    "invalid interval",
+			env:     map[string]string{"PARSE_INTERVAL": "often"},
+			wantErr: `invalid PARSE_INTERVAL "often"`,
+		},
+		{
+			name:    "invalid grid",
+			env:     map[string]string{"REGION_GRID_SIZE": "big"},
+			wantErr: `invalid REGION_GRID_SIZE "big"`,
+		},
+		{
+			name: "collectors trimmed",
+			env:  map[string]string{"COLLECTORS": " power, ,buildings "},
+			want: func(c *Config) { c.Collectors = []string{"power", "buildings"} },
+		},
+		{
+			name: "saves dir with default server",
+			env:  map[string]string{"SAVES_DIR": "/saves"},
+			want: func(c *Config) { c.Sources = []Source{{Server: DefaultServer, Dir: "/saves"}} },
+		},
+		{
+			name: "saves dir with server name",
+			env:  map[string]string{"SAVES_DIR": "/saves", "SERVER_NAME": "main"},
+			want: func(c *Config) { c.Sources = []Source{{Server: "main", Dir: "/saves"}} },
+		},
+		{
+			name: "save sources win over saves dir",
+			env:  map[string]string{"SAVE_SOURCES": "a=/a, b=/b", "SAVES_DIR": "/saves"},
+			want: func(c *Config) { c.Sources = []Source{{Server: "a", Dir: "/a"}, {Server: "b", Dir: "/b"}} },
+		},
+		{
+			name:    "invalid save sources",
+			env:     map[string]string{"SAVE_SOURCES": "a=/a,b"},
+			wantErr: `invalid SAVE_SOURCES: invalid save source "b": want server=dir`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, name := range envNames {
+				t.Setenv(name, "")
+				os.Unsetenv(name)
+			}
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+
+			got := Default()
+			err := got.applyEnv()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("applyEnv() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("applyEnv() error = %v", err)
+			}
+			want := Default()
+			tt.want(&want)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("applyEnv() = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// applyEnv overrides the configuration with any environment variables set
+func (c *Config) applyEnv() error {
+	strs := map[string]*string{
+		"LISTEN_ADDR":       &c.Listen,
+		"JSON_DIR":          &c.JSONDir,
+		"MAP_POINTS_PATH":   &c.MapPoints,
+		"ARCHIVE_DIR":       &c.Archive.Dir,
+		"ARCHIVE_RETENTION": &c.Archive.Retention,
+		"HISTORY_PATH":      &c.History.Path,
+	}
+	for name, dst := range strs {
+		if value, ok := os.LookupEnv(name); ok {
+			*dst = value
+		}
+	}
+
+	bools := map[string]*bool{
+		"STRICT_PARSE":     &c.Strict,
+		"SAVE_TIMESTAMPS":  &c.SaveTimestamps,
+		"ARCHIVE_SUMMARY":  &c.Archive.Summary,
+		"HISTORY_BACKFILL": &c.History.Backfill,
+	}
+	for name, dst := range bools {
+		if value, ok := os.LookupEnv(name); ok && value != "" {
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				return fmt.Errorf("invalid %s %q: want true or false", name, value)
+			}
+			*dst = b
+		}
+	}
+
+	if value := os.Getenv("PARSE_INTERVAL"); value != "" {
+		interval, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("invalid PARSE_INTERVAL %q: %v", value, err)
+		}
+		c.Interval = interval
+	}
+	if value := os.Getenv("REGION_GRID_SIZE"); value != "" {
+		size, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("invalid REGION_GRID_SIZE %q: %v", value, err)
+		}
+		c.Labels.RegionGridSize = size
+	}
+	if value := os.Getenv("COLLECTORS"); value != "" {
+		c.Collectors = splitList(value)
+	}
+
+	if value := os.Getenv("SAVE_SOURCES"); value != "" {
+		sources, err := ParseSources(value)
+		if err != nil {
+			return fmt.Errorf("invalid SAVE_SOURCES: %w", err)
+		}
+		c.Sources = sources
+	} else if dir := os.Getenv("SAVES_DIR"); dir != "" {
+		server := os.Getenv("SERVER_NAME")
+		if server == "" {
+			server = DefaultServer
+		}
+		c.Sources = []Source{{Server: server, Dir: dir}}
+	}
+	return nil
+}
+
+// ParseSources parses comma separated server=dir pairs
+func ParseSources(value string) ([]Source, error) {
+	var sources []Source
+	for _, part := range splitList(value) {
+		server, dir, ok := strings.Cut(part, "=")
+		if !ok || server == "" || dir == "" {
+			return nil, fmt.Errorf("invalid save source %q: want server=dir", part)
+		}
+		sources = append(sources, Source{Server: server, Dir: dir})
+	}
+	return sources, nil
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,62 @@
+package config
+
+import (
+	"flag"
+	"os"
+	"time"
+)
+
+// Flags are command line overrides applied on top of the file and environment
+type Flags struct {
+	fs *flag.FlagSet
+
+	path     string
+	listen   string
+	savesDir string
+	server   string
+	jsonDir  string
+	interval time.Duration
+	strict   bool
+}
+
+// RegisterFlags defines the config flags on fs
+func RegisterFlags(fs *flag.FlagSet) *Flags {
+	f := &Flags{fs: fs}
+	fs.StringVar(&f.path, "config", os.Getenv("CONFIG_FILE"), "path to a YAML config file")
+	fs.StringVar(&f.listen, "listen", "", "HTTP listen address")
+	fs.StringVar(&f.savesDir, "saves-dir", "", "directory of saves, replacing any configured sources")
+	fs.StringVar(&f.server, "server", DefaultServer, "server name for -saves-dir")
+	fs.StringVar(&f.jsonDir, "json-dir", "", "directory for parser JSON output")
+	fs.DurationVar(&f.interval, "interval", 0, "how often to check for new saves")
+	fs.BoolVar(&f.strict, "strict", false, "fail parses that drop properties or find unknown property types")
+	return f
+}
+
+// Load loads the config file and environment, applies the flags set on the
+// command line and validates the result
+func (f *Flags) Load() (Config, error) {
+	cfg, err := Load(f.path)
+	if err != nil {
+		return cfg, err
+	}
+	f.apply(&cfg)
+	return cfg, cfg.Validate()
+}
+
+// apply overrides cfg with the flags set on the command line
+func (f *Flags) apply(cfg *Config) {
+	f.fs.Visit(func(fl *flag.Flag) {
+		switch fl.Name {
+		case "listen":
+			cfg.Listen = f.listen
+		case "saves-dir":
+			cfg.Sources = []Source{{Server: f.server, Dir: f.savesDir}}
+		case "json-dir":
+			cfg.JSONDir = f.jsonDir
+		case "interval":
+			cfg.Interval = f.interval
+		case "strict":
+			cfg.Strict = f.strict
+		}
+	})
+}
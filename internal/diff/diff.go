@@ -0,0 +1,235 @@
+package diff
+
+import (
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/FreekingDean/satisfactory-buddy/internal/catalog"
+	"github.com/FreekingDean/satisfactory-buddy/internal/savefile"
+)
+
+// moveThreshold is the smallest translation, in world units, reported as a move
+const moveThreshold = 1.0
+
+// Report lists everything that changed between two saves
+type Report struct {
+	From        Snapshot         `json:"from"`
+	To          Snapshot         `json:"to"`
+	Added       []Object         `json:"added"`
+	Removed     []Object         `json:"removed"`
+	Moved       []Move           `json:"moved"`
+	Changed     []ObjectChanges  `json:"changed"`
+	Inventories []InventoryDelta `json:"inventories"`
+	Circuits    []CircuitChange  `json:"circuits"`
+	Lightweight map[string]int   `json:"lightweight"`
+}
+
+// Snapshot identifies one side of a diff
+type Snapshot struct {
+	SaveName     string    `json:"saveName"`
+	SessionName  string    `json:"sessionName"`
+	SaveDateTime time.Time `json:"saveDateTime"`
+}
+
+// Object is an object present in only one of the saves
+type Object struct {
+	Name     string            `json:"name"`
+	Type     string            `json:"type"`
+	Location savefile.Vector3D `json:"location"`
+}
+
+// Move is an object whose location changed
+type Move struct {
+	Name     string            `json:"name"`
+	Type     string            `json:"type"`
+	From     savefile.Vector3D `json:"from"`
+	To       savefile.Vector3D `json:"to"`
+	Distance float64           `json:"distance"`
+}
+
+// ObjectChanges lists the properties that changed on one object
+type ObjectChanges struct {
+	Name       string           `json:"name"`
+	Type       string           `json:"type"`
+	Properties []PropertyChange `json:"properties"`
+}
+
+// PropertyChange is a property's value before and after, nil when absent
+type PropertyChange struct {
+	Name string      `json:"name"`
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+// InventoryDelta is the change in item counts held by one inventory component
+type InventoryDelta struct {
+	Name  string         `json:"name"`
+	Owner string         `json:"owner"`
+	Items map[string]int `json:"items"`
+}
+
+// CircuitChange is a power connection that moved to another circuit, -1 when unconnected
+type CircuitChange struct {
+	Name string `json:"name"`
+	From int    `json:"from"`
+	To   int    `json:"to"`
+}
+
+// Compare reports the differences between two saves, keyed by InstanceName
+func Compare(from, to *savefile.SaveFile) Report {
+	report := Report{
+		From:        snapshot(from),
+		To:          snapshot(to),
+		Lightweight: lightweightDelta(from, to),
+	}
+
+	before := from.AllGameObjects()
+	after := to.AllGameObjects()
+
+	for _, name := range sortedNames(before) {
+		old := before[name]
+		obj, ok := after[name]
+		if !ok {
+			report.Removed = append(report.Removed, object(old))
+			if items := inventoryDelta(inventory(old), nil); len(items) > 0 {
+				report.Inventories = append(report.Inventories, InventoryDelta{Name: name, Owner: old.ParentEntityName, Items: items})
+			}
+			if circuit := from.GetCircuit(name); circuit != -1 {
+				report.Circuits = append(report.Circuits, CircuitChange{Name: name, From: circuit, To: -1})
+			}
+			continue
+		}
+
+		if d := savefile.Distance(old.Transform.Translation, obj.Transform.Translation); d >= moveThreshold {
+			report.Moved = append(report.Moved, Move{
+				Name:     name,
+				Type:     obj.SimpleType(),
+				From:     old.Transform.Translation,
+				To:       obj.Transform.Translation,
+				Distance: d,
+			})
+		}
+		if changes := propertyChanges(old.Properties, obj.Properties); len(changes) > 0 {
+			report.Changed = append(report.Changed, ObjectChanges{Name: name, Type: obj.SimpleType(), Properties: changes})
+		}
+		if items := inventoryDelta(inventory(old), inventory(obj)); len(items) > 0 {
+			report.Inventories = append(report.Inventories, InventoryDelta{Name: name, Owner: obj.ParentEntityName, Items: items})
+		}
+		if oldCircuit, newCircuit := from.GetCircuit(name), to.GetCircuit(name); oldCircuit != newCircuit {
+			report.Circuits = append(report.Circuits, CircuitChange{Name: name, From: oldCircuit, To: newCircuit})
+		}
+	}
+
+	for _, name := range sortedNames(after) {
+		obj := after[name]
+		if _, ok := before[name]; ok {
+			continue
+		}
+		report.Added = append(report.Added, object(obj))
+		if items := inventoryDelta(nil, inventory(obj)); len(items) > 0 {
+			report.Inventories = append(report.Inventories, InventoryDelta{Name: name, Owner: obj.ParentEntityName, Items: items})
+		}
+		if circuit := to.GetCircuit(name); circuit != -1 {
+			report.Circuits = append(report.Circuits, CircuitChange{Name: name, From: -1, To: circuit})
+		}
+	}
+
+	return report
+}
+
+func snapshot(sf *savefile.SaveFile) Snapshot {
+	return Snapshot{
+		SaveName:     sf.Header.SaveName,
+		SessionName:  sf.Header.SessionName,
+		SaveDateTime: sf.Header.SaveDateTime.Time,
+	}
+}
+
+func object(obj *savefile.GameObject) Object {
+	return Object{Name: obj.InstanceName, Type: obj.SimpleType(), Location: obj.Transform.Translation}
+}
+
+func sortedNames(objects map[string]*savefile.GameObject) []string {
+	names := make([]string, 0, len(objects))
+	for name := range objects {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// propertyChanges compares every property by its decoded value
+func propertyChanges(before, after savefile.PropertyContainer) []PropertyChange {
+	names := make(map[string]bool)
+	for _, name := range before.Names() {
+		names[name] = true
+	}
+	for _, name := range after.Names() {
+		names[name] = true
+	}
+
+	var changes []PropertyChange
+	for name := range names {
+		old, _ := before.GetPath(name)
+		value, _ := after.GetPath(name)
+		if !reflect.DeepEqual(old, value) {
+			changes = append(changes, PropertyChange{Name: name, From: old, To: value})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Name < changes[j].Name
+	})
+	return changes
+}
+
+// inventory totals the items held by an inventory component by item class
+func inventory(obj *savefile.GameObject) map[string]int {
+	// Stacks that fail to decode are left out rather than hiding the whole
+	// inventory
+	stacks, _ := savefile.StructArrayAs[savefile.InventoryStack](obj.Properties, "mInventoryStacks")
+	if stacks == nil {
+		return nil
+	}
+
+	items := make(map[string]int)
+	for _, stack := range stacks {
+		if item := catalog.ClassName(stack.Item.ItemClass); item != "" && stack.NumItems > 0 {
+			items[item] += stack.NumItems
+		}
+	}
+	return items
+}
+
+func inventoryDelta(before, after map[string]int) map[string]int {
+	delta := make(map[string]int)
+	for item, count := range after {
+		if d := count - before[item]; d != 0 {
+			delta[item] = d
+		}
+	}
+	for item, count := range before {
+		if _, ok := after[item]; !ok {
+			delta[item] = -count
+		}
+	}
+	return delta
+}
+
+// lightweightDelta reports the change in lightweight buildable counts by class,
+// since they have no instance names to match on
+func lightweightDelta(from, to *savefile.SaveFile) map[string]int {
+	counts := make(map[string]int)
+	for _, buildable := range from.LightweightBuildables() {
+		counts[buildable.SimpleType()]--
+	}
+	for _, buildable := range to.LightweightBuildables() {
+		counts[buildable.SimpleType()]++
+	}
+	for class, count := range counts {
+		if count == 0 {
+			delete(counts, class)
+		}
+	}
+	return counts
+}
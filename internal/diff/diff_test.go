@@ -0,0 +1,174 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/FreekingDean/satisfactory-buddy/internal/savefile"
+)
+
+// save decodes a save holding the given objects, written as JSON
+func save(t *testing.T, objects ...string) *savefile.SaveFile {
+	t.Helper()
+	data := `{"header":{"saveName":"test"},"levels":{"L":{"objects":[` + strings.Join(objects, ",") + `]}}}`
+	var sf savefile.SaveFile
+	if err := json.Unmarshal([]byte(data), &sf); err != nil {
+		t.Fatalf("failed to decode save: %v", err)
+	}
+	return &sf
+}
+
+func building(name string, x float64, properties string) string {
+	return fmt.Sprintf(`{"typePath":"/Game/Build_A.Build_A_C","instanceName":%q,"transform":{"translation":{"x":%g,"y":0,"z":0}},"properties":{%s}}`, name, x, properties)
+}
+
+func storage(name string, counts ...int) string {
+	var stacks []string
+	for _, count := range counts {
+		stacks = append(stacks, fmt.Sprintf(`{"properties":{
+			"Item":{"type":"StructProperty","subtype":"InventoryItem","value":{"itemReference":{"pathName":"/Game/Desc_Screw.Desc_Screw_C"}}},
+			"NumItems":{"type":"Int32Property","value":%d}}}`, count))
+	}
+	return fmt.Sprintf(`{"typePath":"/Script/FactoryGame.FGInventoryComponent","instanceName":%q,"parentEntityName":"Owner","properties":{
+		"mInventoryStacks":{"type":"StructArrayProperty","subtype":"InventoryStack","values":[%s]}}}`, name, strings.Join(stacks, ","))
+}
+
+func circuit(id int, components ...string) string {
+	var refs []string
+	for _, component := range components {
+		refs = append(refs, fmt.Sprintf(`{"levelName":"L","pathName":%q}`, component))
+	}
+	return fmt.Sprintf(`{"typePath":"/Script/FactoryGame.FGPowerCircuit","instanceName":"Circuit_%d","properties":{
+		"mCircuitID":{"type":"Int32Property","value":%d},
+		"mComponents":{"type":"ObjectArrayProperty","values":[%s]}}}`, id, id, strings.Join(refs, ","))
+}
+
+func lightweight(classes ...string) string {
+	var buildables []string
+	for _, class := range classes {
+		buildables = append(buildables, fmt.Sprintf(`{"typeReference":{"pathName":"/Game/%s.%s"},"instances":[{"transform":{}}]}`, class, class))
+	}
+	return `{"typePath":"/Script/FactoryGame.FGLightweightBuildableSubsystem","instanceName":"Lightweight","properties":{},
+		"specialProperties":{"buildables":[` + strings.Join(buildables, ",") + `]}}`
+}
+
+func names[T any](items []T, name func(T) string) []string {
+	result := []string{}
+	for _, item := range items {
+		result = append(result, name(item))
+	}
+	return result
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		name   string
+		from   []string
+		to     []string
+		check  func(Report) interface{}
+		expect interface{}
+	}{
+		{
+			name:   "identical saves",
+			from:   []string{building("A_1", 100, "")},
+			to:     []string{building("A_1", 100, "")},
+			check:  func(r Report) interface{} { return len(r.Added) + len(r.Removed) + len(r.Moved) + len(r.Changed) },
+			expect: 0,
+		},
+		{
+			name: "added and removed",
+			from: []string{building("A_1", 100, ""), building("A_2", 200, "")},
+			to:   []string{building("A_2", 200, ""), building("A_3", 300, "")},
+			check: func(r Report) interface{} {
+				return [][]string{names(r.Added, objectName), names(r.Removed, objectName)}
+			},
+			expect: [][]string{{"A_3"}, {"A_1"}},
+		},
+		{
+			name: "moved beyond threshold",
+			from: []string{building("A_1", 100, ""), building("A_2", 100, "")},
+			to:   []string{building("A_1", 400, ""), building("A_2", 100.5, "")},
+			check: func(r Report) interface{} {
+				return names(r.Moved, func(m Move) string { return fmt.Sprintf("%s %g", m.Name, m.Distance) })
+			},
+			expect: []string{"A_1 300"},
+		},
+		{
+			name: "property changes",
+			from: []string{building("A_1", 100, `"mHealth":{"type":"FloatProperty","value":50},"mOld":{"type":"BoolProperty","value":true}`)},
+			to:   []string{building("A_1", 100, `"mHealth":{"type":"FloatProperty","value":75},"mNew":{"type":"StrProperty","value":"x"}`)},
+			check: func(r Report) interface{} {
+				var changes []string
+				for _, object := range r.Changed {
+					for _, change := range object.Properties {
+						changes = append(changes, fmt.Sprintf("%s %v->%v", change.Name, change.From, change.To))
+					}
+				}
+				return changes
+			},
+			expect: []string{"mHealth 50->75", "mNew <nil>->x", "mOld true-><nil>"},
+		},
+		{
+			name: "inventory delta",
+			from: []string{storage("Inv_1", 100, 50)},
+			to:   []string{storage("Inv_1", 100, 20), storage("Inv_2", 5)},
+			check: func(r Report) interface{} {
+				return names(r.Inventories, func(d InventoryDelta) string {
+					return fmt.Sprintf("%s %s %v", d.Name, d.Owner, d.Items)
+				})
+			},
+			expect: []string{"Inv_1 Owner map[Desc_Screw_C:-30]", "Inv_2 Owner map[Desc_Screw_C:5]"},
+		},
+		{
+			name: "circuit changes",
+			from: []string{building("Conn_1", 0, ""), building("Conn_2", 0, ""), circuit(1, "Conn_1", "Conn_2")},
+			to:   []string{building("Conn_1", 0, ""), building("Conn_2", 0, ""), building("Conn_3", 0, ""), circuit(1, "Conn_1"), circuit(2, "Conn_2", "Conn_3")},
+			check: func(r Report) interface{} {
+				return names(r.Circuits, func(c CircuitChange) string { return fmt.Sprintf("%s %d->%d", c.Name, c.From, c.To) })
+			},
+			expect: []string{"Conn_2 1->2", "Conn_3 -1->2"},
+		},
+		{
+			name: "removed inventory holder",
+			from: []string{storage("Inv_1", 100, 50), storage("Inv_2", 5)},
+			to:   []string{storage("Inv_2", 5)},
+			check: func(r Report) interface{} {
+				return names(r.Inventories, func(d InventoryDelta) string {
+					return fmt.Sprintf("%s %s %v", d.Name, d.Owner, d.Items)
+				})
+			},
+			expect: []string{"Inv_1 Owner map[Desc_Screw_C:-150]"},
+		},
+		{
+			name: "removed powered building",
+			from: []string{building("Conn_1", 0, ""), building("Conn_2", 0, ""), circuit(1, "Conn_1", "Conn_2")},
+			to:   []string{building("Conn_2", 0, ""), circuit(1, "Conn_2")},
+			check: func(r Report) interface{} {
+				return names(r.Circuits, func(c CircuitChange) string { return fmt.Sprintf("%s %d->%d", c.Name, c.From, c.To) })
+			},
+			expect: []string{"Conn_1 1->-1"},
+		},
+		{
+			name:   "lightweight counts",
+			from:   []string{lightweight("Build_Foundation_C", "Build_Wall_C")},
+			to:     []string{lightweight("Build_Foundation_C", "Build_Foundation_C", "Build_Ramp_C")},
+			check:  func(r Report) interface{} { return r.Lightweight },
+			expect: map[string]int{"Build_Foundation_C": 1, "Build_Wall_C": -1, "Build_Ramp_C": 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := Compare(save(t, tt.from...), save(t, tt.to...))
+			if got := tt.check(report); !reflect.DeepEqual(got, tt.expect) {
+				t.Errorf("Compare() = %v, want %v", got, tt.expect)
+			}
+		})
+	}
+}
+
+func objectName(o Object) string {
+	return o.Name
+}
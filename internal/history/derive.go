@@ -0,0 +1,102 @@
+package history
+
+import (
+	"log"
+
+	"github.com/FreekingDean/satisfactory-buddy/internal/archive"
+	"github.com/FreekingDean/satisfactory-buddy/internal/catalog"
+	"github.com/FreekingDean/satisfactory-buddy/internal/savefile"
+)
+
+// Series names recorded for every save. Production and inventory series are
+// suffixed with the item class, e.g. "production_per_minute.Desc_SpaceElevatorPart_1_C".
+const (
+	SeriesPowerGeneration  = "power_generation_mw"
+	SeriesPowerConsumption = "power_consumption_mw"
+	SeriesProduction       = "production_per_minute"
+	SeriesInventory        = "inventory_items"
+	SeriesSinkPoints       = "sink_points"
+	SeriesSinkCoupons      = "sink_coupons"
+)
+
+// Derive computes the recorded values for a save. The caller sets the
+// sample's Server, which the save itself does not record.
+func Derive(sf *savefile.SaveFile) Sample {
+	values := make(map[string]float64)
+
+	var generation, consumption float64
+	for _, obj := range sf.ObjectsOfType("FGPowerInfoComponent") {
+		g, c := catalog.PowerFlow(sf, obj)
+		generation += g
+		consumption += c
+	}
+	values[SeriesPowerGeneration] = generation
+	values[SeriesPowerConsumption] = consumption
+
+	for item, rate := range catalog.ProductionRates(sf) {
+		values[SeriesProduction+"."+item] = rate
+	}
+
+	for _, obj := range sf.AllGameObjects() {
+		stacks, err := savefile.StructArrayAs[savefile.InventoryStack](obj.Properties, "mInventoryStacks")
+		if stacks == nil {
+			continue
+		}
+		if err != nil {
+			log.Printf("Warning: %s: %v", obj.InstanceName, err)
+		}
+		for _, stack := range stacks {
+			if item := catalog.ClassName(stack.Item.ItemClass); item != "" && stack.NumItems > 0 {
+				values[SeriesInventory+"."+item] += float64(stack.NumItems)
+			}
+		}
+	}
+
+	for _, sink := range sf.ObjectsOfType("FGResourceSinkSubsystem") {
+		// Newer saves track points per sink track, older ones a single total
+		if points, err := sink.GetIntArray("mTotalPoints"); err == nil {
+			for _, p := range points {
+				values[SeriesSinkPoints] += float64(p)
+			}
+		} else if points, err := sink.GetInt("mTotalResourceSinkPoints"); err == nil {
+			values[SeriesSinkPoints] += float64(points)
+		}
+		if coupons, err := sink.GetInt("mNumResourceSinkCoupons"); err == nil {
+			values[SeriesSinkCoupons] += float64(coupons)
+		}
+	}
+
+	return Sample{
+		Time:    sf.Header.SaveDateTime.Time,
+		Session: sf.Header.SessionName,
+		Values:  values,
+	}
+}
+
+// Backfill records a sample for every archived save not yet in the store,
+// loading each with load. Saves that fail to load are logged and skipped.
+// It returns the number of samples added.
+func (s *Store) Backfill(store *archive.Store, load func(archive.Entry) (*savefile.SaveFile, error)) (int, error) {
+	added := 0
+	for _, entry := range store.List() {
+		if s.Has(entry.Server, entry.SessionName, entry.SaveTime) {
+			continue
+		}
+
+		sf, err := load(entry)
+		if err != nil {
+			log.Printf("Warning: skipping archived save %s in history backfill: %v", entry.Hash, err)
+			continue
+		}
+		sample := Derive(sf)
+		sample.Server = entry.Server
+		ok, err := s.Record(sample)
+		if err != nil {
+			return added, err
+		}
+		if ok {
+			added++
+		}
+	}
+	return added, nil
+}
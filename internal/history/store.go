@@ -0,0 +1,176 @@
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Sample is the set of derived values for one save, keyed by its save time
+type Sample struct {
+	Time    time.Time          `json:"time"`
+	Server  string             `json:"server"`
+	Session string             `json:"session"`
+	Values  map[string]float64 `json:"values"`
+}
+
+// Store is an append-only file of samples, one JSON object per line, that is
+// held in memory for queries
+type Store struct {
+	path string
+
+	mu      sync.RWMutex
+	samples []Sample
+	seen    map[sampleKey]bool
+}
+
+type sampleKey struct {
+	server  string
+	session string
+	time    int64
+}
+
+// Open loads the samples stored at path, creating the file when it is missing
+func Open(path string) (*Store, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create history directory: %w", err)
+	}
+
+	store := &Store{path: path, seen: make(map[sampleKey]bool)}
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		var sample Sample
+		if err := json.Unmarshal(scanner.Bytes(), &sample); err != nil {
+			return nil, fmt.Errorf("failed to decode history line %d: %w", line, err)
+		}
+		store.insert(sample)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+	return store, nil
+}
+
+// Record appends a sample unless one already exists for its server, session and time
+func (s *Store) Record(sample Sample) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.seen[keyOf(sample)] {
+		return false, nil
+	}
+
+	data, err := json.Marshal(sample)
+	if err != nil {
+		return false, fmt.Errorf("failed to encode sample: %w", err)
+	}
+	file, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return false, fmt.Errorf("failed to open history: %w", err)
+	}
+	defer file.Close()
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		return false, fmt.Errorf("failed to write sample: %w", err)
+	}
+
+	s.insert(sample)
+	return true, nil
+}
+
+// Has reports whether a sample exists for the server's session at t
+func (s *Store) Has(server, session string, t time.Time) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.seen[sampleKey{server, session, t.UnixNano()}]
+}
+
+// Query returns samples between from and to inclusive, oldest first. Empty
+// server, session or series match everything; a zero from or to leaves that
+// end open.
+func (s *Store) Query(server, session string, series []string, from, to time.Time) []Sample {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var result []Sample
+	for _, sample := range s.samples {
+		if server != "" && sample.Server != server {
+			continue
+		}
+		if session != "" && sample.Session != session {
+			continue
+		}
+		if !from.IsZero() && sample.Time.Before(from) {
+			continue
+		}
+		if !to.IsZero() && sample.Time.After(to) {
+			continue
+		}
+
+		if len(series) > 0 {
+			values := make(map[string]float64, len(series))
+			for _, name := range series {
+				if v, ok := sample.Values[name]; ok {
+					values[name] = v
+				}
+			}
+			sample.Values = values
+		}
+		result = append(result, sample)
+	}
+	return result
+}
+
+// Series returns the name of every series recorded, sorted
+func (s *Store) Series() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	seen := make(map[string]bool)
+	for _, sample := range s.samples {
+		for name := range sample.Values {
+			seen[name] = true
+		}
+	}
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// insert adds a sample keeping samples ordered by time
+func (s *Store) insert(sample Sample) {
+	key := keyOf(sample)
+	if s.seen[key] {
+		return
+	}
+	s.seen[key] = true
+
+	i := sort.Search(len(s.samples), func(i int) bool {
+		return s.samples[i].Time.After(sample.Time)
+	})
+	s.samples = append(s.samples, Sample{})
+	copy(s.samples[i+1:], s.samples[i:])
+	s.samples[i] = sample
+}
+
+func keyOf(sample Sample) sampleKey {
+	return sampleKey{sample.Server, sample.Session, sample.Time.UnixNano()}
+}
@@ -0,0 +1,126 @@
+package history
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestStoreQuery(t *testing.T) {
+	base := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	at := func(hours int) time.Time { return base.Add(time.Duration(hours) * time.Hour) }
+
+	store, err := Open(filepath.Join(t.TempDir(), "history", "samples.jsonl"))
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	// Recorded out of order and with a duplicate to check ordering and dedupe
+	for _, sample := range []Sample{
+		{Time: at(2), Server: "s1", Session: "alpha", Values: map[string]float64{"power": 20, "sink": 2}},
+		{Time: at(0), Server: "s1", Session: "alpha", Values: map[string]float64{"power": 10, "sink": 1}},
+		{Time: at(1), Server: "s1", Session: "beta", Values: map[string]float64{"power": 5}},
+		{Time: at(1), Server: "s2", Session: "alpha", Values: map[string]float64{"power": 7}},
+		{Time: at(2), Server: "s1", Session: "alpha", Values: map[string]float64{"power": 99}},
+	} {
+		if _, err := store.Record(sample); err != nil {
+			t.Fatalf("Record() error = %v", err)
+		}
+	}
+
+	type result struct {
+		Hour    int
+		Server  string
+		Session string
+		Values  map[string]float64
+	}
+	tests := []struct {
+		name    string
+		server  string
+		session string
+		series  []string
+		from    time.Time
+		to      time.Time
+		want    []result
+	}{
+		{
+			name: "everything oldest first",
+			want: []result{
+				{0, "s1", "alpha", map[string]float64{"power": 10, "sink": 1}},
+				{1, "s1", "beta", map[string]float64{"power": 5}},
+				{1, "s2", "alpha", map[string]float64{"power": 7}},
+				{2, "s1", "alpha", map[string]float64{"power": 20, "sink": 2}},
+			},
+		},
+		{
+			name:    "session across servers",
+			session: "alpha",
+			series:  []string{"power"},
+			want: []result{
+				{0, "s1", "alpha", map[string]float64{"power": 10}},
+				{1, "s2", "alpha", map[string]float64{"power": 7}},
+				{2, "s1", "alpha", map[string]float64{"power": 20}},
+			},
+		},
+		{
+			name:    "server and session",
+			server:  "s1",
+			session: "alpha",
+			series:  []string{"sink"},
+			want: []result{
+				{0, "s1", "alpha", map[string]float64{"sink": 1}},
+				{2, "s1", "alpha", map[string]float64{"sink": 2}},
+			},
+		},
+		{
+			name:   "missing series leaves empty values",
+			server: "s1",
+			series: []string{"sink"},
+			from:   at(1),
+			to:     at(1),
+			want:   []result{{1, "s1", "beta", map[string]float64{}}},
+		},
+		{
+			name: "inclusive range",
+			from: at(1),
+			to:   at(2),
+			want: []result{
+				{1, "s1", "beta", map[string]float64{"power": 5}},
+				{1, "s2", "alpha", map[string]float64{"power": 7}},
+				{2, "s1", "alpha", map[string]float64{"power": 20, "sink": 2}},
+			},
+		},
+		{
+			name: "open start",
+			to:   at(0),
+			want: []result{{0, "s1", "alpha", map[string]float64{"power": 10, "sink": 1}}},
+		},
+		{
+			name:   "unknown server",
+			server: "s3",
+			want:   nil,
+		},
+	}
+	check := func(t *testing.T, store *Store) {
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				var got []result
+				for _, sample := range store.Query(tt.server, tt.session, tt.series, tt.from, tt.to) {
+					got = append(got, result{int(sample.Time.Sub(base).Hours()), sample.Server, sample.Session, sample.Values})
+				}
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("Query() = %v, want %v", got, tt.want)
+				}
+			})
+		}
+	}
+
+	check(t, store)
+
+	// Samples read back from disk answer the same queries
+	reopened, err := Open(store.path)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	t.Run("reopened", func(t *testing.T) { check(t, reopened) })
+}
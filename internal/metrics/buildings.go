@@ -0,0 +1,61 @@
+package metrics
+
+import (
+	"fmt"
+	"log"
+	"math"
+
+	"github.com/FreekingDean/satisfactory-buddy/internal/catalog"
+	"github.com/FreekingDean/satisfactory-buddy/internal/savefile"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// Building census metrics
+	buildingsTotal = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "buildings_total",
+			Help: "Number of buildables by type, category and optional map region",
+		},
+		sessionLabels("type", "category", "region"),
+	)
+)
+
+type buildingKey struct {
+	class  string
+	region string
+}
+
+// updateBuildingMetrics counts every Build_* object, including lightweight buildables
+func (mc *MetricsCollector) updateBuildingMetrics() {
+	counts := make(map[buildingKey]int)
+	for typePath := range mc.saveFile.TypeCounts() {
+		class := catalog.ClassName(typePath)
+		if !catalog.IsBuildable(class) {
+			continue
+		}
+		for _, obj := range mc.saveFile.ObjectsOfType(typePath) {
+			counts[buildingKey{class, mc.region(obj.Transform.Translation)}]++
+		}
+	}
+	for _, buildable := range mc.saveFile.LightweightBuildables() {
+		counts[buildingKey{buildable.SimpleType(), mc.region(buildable.Transform.Translation)}]++
+	}
+
+	for key, count := range counts {
+		mc.gauge(buildingsTotal).WithLabelValues(key.class, catalog.BuildingCategory(key.class), key.region).Set(float64(count))
+	}
+
+	log.Printf("Updated building metrics for %d building types", len(counts))
+}
+
+// region returns the grid cell for a world location, or "" when regions are disabled
+func (mc *MetricsCollector) region(location savefile.Vector3D) string {
+	if mc.options.RegionGridSize <= 0 {
+		return ""
+	}
+	// World coordinates are in centimeters
+	cell := mc.options.RegionGridSize * 100
+	return fmt.Sprintf("x%d_y%d", int(math.Floor(location.X/cell)), int(math.Floor(location.Y/cell)))
+}
@@ -0,0 +1,79 @@
+package metrics
+
+import (
+	"log"
+
+	"github.com/FreekingDean/satisfactory-buddy/internal/catalog"
+	"github.com/FreekingDean/satisfactory-buddy/internal/savefile"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// Dimensional depot metrics
+	centralStorageItems = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "central_storage_items",
+			Help: "Number of items stored in the dimensional depot",
+		},
+		sessionLabels("item", "item_name"),
+	)
+
+	centralStorageItemLimit = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "central_storage_item_limit",
+			Help: "Maximum number of items the dimensional depot can hold per item type",
+		},
+		sessionLabels("item", "item_name"),
+	)
+
+	centralStorageFillPercent = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "central_storage_fill_percent",
+			Help: "How full the dimensional depot is for each item type, in percent",
+		},
+		sessionLabels("item", "item_name"),
+	)
+
+	centralStorageUploaders = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "central_storage_uploaders",
+			Help: "Number of dimensional depot uploaders built",
+		},
+		sessionLabels(),
+	)
+)
+
+// updateCentralStorageMetrics reports dimensional depot contents and limits
+func (mc *MetricsCollector) updateCentralStorageMetrics() {
+	uploaders := len(mc.saveFile.ObjectsOfType("Build_CentralStorage_C"))
+	stored := make(map[string]int)
+	stackLimit := 1
+
+	for _, obj := range mc.saveFile.ObjectsOfType("FGCentralStorageSubsystem") {
+		if items, err := savefile.StructArrayAs[savefile.ItemAmount](obj.Properties, "mStoredItems"); items != nil {
+			if err != nil {
+				log.Printf("Warning: %v", err)
+			}
+			for item, amount := range itemAmounts(items) {
+				stored[item] += amount
+			}
+		}
+		// Each depot upgrade adds one more stack per item
+		if upgrades, err := obj.GetInt("mCentralStorageItemStackLimitUpgradeLevel"); err == nil {
+			stackLimit += int(upgrades)
+		}
+	}
+
+	mc.gauge(centralStorageUploaders).WithLabelValues().Set(float64(uploaders))
+	for item, amount := range stored {
+		itemName := catalog.ItemName(item)
+		limit := catalog.StackSize(item) * stackLimit
+
+		mc.gauge(centralStorageItems).WithLabelValues(item, itemName).Set(float64(amount))
+		mc.gauge(centralStorageItemLimit).WithLabelValues(item, itemName).Set(float64(limit))
+		mc.gauge(centralStorageFillPercent).WithLabelValues(item, itemName).Set(float64(amount) / float64(limit) * 100)
+	}
+
+	log.Printf("Updated central storage metrics for %d items and %d uploaders", len(stored), uploaders)
+}
@@ -0,0 +1,48 @@
+package metrics
+
+import (
+	"log"
+
+	"github.com/FreekingDean/satisfactory-buddy/internal/collectibles"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// Collectible metrics
+	collectiblesTotal = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "collectibles_total",
+			Help: "Number of known collectible locations on the map",
+		},
+		sessionLabels("kind"),
+	)
+
+	collectiblesCollected = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "collectibles_collected",
+			Help: "Number of collectibles that have been picked up or opened",
+		},
+		sessionLabels("kind"),
+	)
+
+	collectiblesRemaining = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "collectibles_remaining",
+			Help: "Number of collectibles still left on the map",
+		},
+		sessionLabels("kind"),
+	)
+)
+
+// updateCollectibleMetrics reports collected and remaining collectibles by kind
+func (mc *MetricsCollector) updateCollectibleMetrics() {
+	items := collectibles.Track(mc.saveFile)
+	for kind, summary := range collectibles.Summarize(items) {
+		mc.gauge(collectiblesTotal).WithLabelValues(kind).Set(float64(summary.Total))
+		mc.gauge(collectiblesCollected).WithLabelValues(kind).Set(float64(summary.Collected))
+		mc.gauge(collectiblesRemaining).WithLabelValues(kind).Set(float64(summary.Remaining))
+	}
+
+	log.Printf("Updated collectible metrics for %d collectibles", len(items))
+}
@@ -0,0 +1,38 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// Load loop metrics. These outlive a single update, so unlike the save
+	// metrics they are only removed when the session is forgotten.
+	parseErrors = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "parse_errors_total",
+			Help: "Number of failed attempts to read or parse a save, with an empty session for directory read errors",
+		},
+		sessionLabels(),
+	)
+
+	lastSuccessfulParse = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "last_successful_parse_timestamp",
+			Help: "Unix time the session's save was last parsed successfully",
+		},
+		sessionLabels(),
+	)
+)
+
+// ParseFailed counts a failed attempt to read or parse a session's save
+func ParseFailed(server, session string) {
+	parseErrors.WithLabelValues(server, session).Inc()
+}
+
+// ParseSucceeded records when a session's save was last parsed successfully
+func ParseSucceeded(server, session string, at time.Time) {
+	lastSuccessfulParse.WithLabelValues(server, session).Set(float64(at.Unix()))
+}
@@ -1,9 +1,12 @@
 package metrics
 
 import (
+	"fmt"
 	"log"
+	"time"
 
 	"github.com/FreekingDean/satisfactory-buddy/internal/savefile"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
 var (
@@ -19,25 +22,184 @@ var (
 // )
 )
 
+// Options configures optional metric behaviour
+type Options struct {
+	// RegionGridSize splits the map into square regions of this many meters
+	// for the buildings_total region label. Zero disables regions.
+	RegionGridSize float64
+
+	// Server names the save source for the server label on every metric
+	Server string
+
+	// Collectors lists the collectors to run by name. Empty runs all of them.
+	Collectors []string
+}
+
+// collectors lists every collector by the name used to enable it
+var collectors = []struct {
+	name   string
+	update func(*MetricsCollector)
+}{
+	{"power", (*MetricsCollector).updatePowerMetrics},
+	{"space_elevator", (*MetricsCollector).updateSpaceElevatorMetrics},
+	{"collectibles", (*MetricsCollector).updateCollectibleMetrics},
+	{"players", (*MetricsCollector).updatePlayerMetrics},
+	{"statistics", (*MetricsCollector).updateStatisticsMetrics},
+	{"buildings", (*MetricsCollector).updateBuildingMetrics},
+	{"central_storage", (*MetricsCollector).updateCentralStorageMetrics},
+	{"parse", (*MetricsCollector).updateParseMetrics},
+	{"save", (*MetricsCollector).updateSaveMetrics},
+}
+
+// CollectorNames returns the name of every collector that can be enabled
+func CollectorNames() []string {
+	names := make([]string, len(collectors))
+	for i, c := range collectors {
+		names[i] = c.name
+	}
+	return names
+}
+
 // MetricsCollector handles collecting and updating Prometheus metrics from save file data
 type MetricsCollector struct {
 	saveFile *savefile.SaveFile
+	options  Options
+	labels   prometheus.Labels
 }
 
 // NewMetricsCollector creates a new metrics collector
-func NewMetricsCollector(saveFile *savefile.SaveFile) *MetricsCollector {
+func NewMetricsCollector(saveFile *savefile.SaveFile, options Options) *MetricsCollector {
 	return &MetricsCollector{
 		saveFile: saveFile,
+		options:  options,
+		labels: prometheus.Labels{
+			"server":  options.Server,
+			"session": saveFile.Header.SessionName,
+		},
 	}
 }
 
-// UpdateMetrics updates all Prometheus metrics with current save file data
-func (mc *MetricsCollector) UpdateMetrics() {
+// sessionLabels prefixes a metric's labels with the server and session labels
+// shared by every save metric
+func sessionLabels(labels ...string) []string {
+	return append([]string{"server", "session"}, labels...)
+}
+
+// gauge returns vec curried with the collector's server and session
+func (mc *MetricsCollector) gauge(vec *prometheus.GaugeVec) *prometheus.GaugeVec {
+	return vec.MustCurryWith(mc.labels)
+}
+
+// counter returns vec curried with the collector's server and session
+func (mc *MetricsCollector) counter(vec *prometheus.CounterVec) *prometheus.CounterVec {
+	return vec.MustCurryWith(mc.labels)
+}
+
+// histogram returns vec curried with the collector's server and session
+func (mc *MetricsCollector) histogram(vec *prometheus.HistogramVec) prometheus.ObserverVec {
+	return vec.MustCurryWith(mc.labels)
+}
+
+// Forget removes every metric reported for a session that is no longer present
+func Forget(server, session string) {
+	labels := prometheus.Labels{"server": server, "session": session}
+	deleteSession(labels)
+	parseErrors.DeletePartialMatch(labels)
+	lastSuccessfulParse.DeletePartialMatch(labels)
+	parsePhaseDuration.DeletePartialMatch(labels)
+	collectorDuration.DeletePartialMatch(labels)
+	collectorErrors.DeletePartialMatch(labels)
+}
+
+// CollectorError is a collector that failed while updating a session
+type CollectorError struct {
+	Collector string `json:"collector"`
+	Error     string `json:"error"`
+}
+
+// UpdateMetrics updates all Prometheus metrics with current save file data.
+// A collector failing on unexpected save data does not stop the others, it is
+// reported in the returned errors instead.
+func (mc *MetricsCollector) UpdateMetrics() []CollectorError {
 	log.Println("Updating Prometheus metrics from save file...")
 
-	// Clear existing metrics
-	powerGeneration.Reset()
-	powerConsumption.Reset()
+	// Clear existing metrics for this session only, leaving other sessions intact
+	deleteSession(mc.labels)
+
+	var errs []CollectorError
+	start := time.Now()
+	for _, c := range collectors {
+		if !mc.enabled(c.name) {
+			continue
+		}
+		collectorStart := time.Now()
+		err := mc.run(c.update)
+		mc.observeCollector(c.name, time.Since(collectorStart), err)
+		if err != nil {
+			log.Printf("Warning: %s collector failed: %v", c.name, err)
+			errs = append(errs, CollectorError{Collector: c.name, Error: err.Error()})
+		}
+	}
+	mc.histogram(parsePhaseDuration).WithLabelValues(PhaseCollect).Observe(time.Since(start).Seconds())
+	return errs
+}
+
+// run calls update, turning a panic into an error
+func (mc *MetricsCollector) run(update func(*MetricsCollector)) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	update(mc)
+	return nil
+}
+
+// enabled reports whether the named collector should run
+func (mc *MetricsCollector) enabled(name string) bool {
+	if len(mc.options.Collectors) == 0 {
+		return true
+	}
+	for _, enabled := range mc.options.Collectors {
+		if enabled == name {
+			return true
+		}
+	}
+	return false
+}
 
-	mc.updatePowerMetrics()
+// deleteSession removes every metric matching the server and session labels
+func deleteSession(labels prometheus.Labels) {
+	powerGeneration.DeletePartialMatch(labels)
+	powerConsumption.DeletePartialMatch(labels)
+	powerMaxConsumption.DeletePartialMatch(labels)
+	spaceElevatorPhase.DeletePartialMatch(labels)
+	spaceElevatorPartRequired.DeletePartialMatch(labels)
+	spaceElevatorPartDelivered.DeletePartialMatch(labels)
+	spaceElevatorPartRemaining.DeletePartialMatch(labels)
+	spaceElevatorPartProduction.DeletePartialMatch(labels)
+	spaceElevatorPartETA.DeletePartialMatch(labels)
+	spaceElevatorPhaseETA.DeletePartialMatch(labels)
+	collectiblesTotal.DeletePartialMatch(labels)
+	collectiblesCollected.DeletePartialMatch(labels)
+	collectiblesRemaining.DeletePartialMatch(labels)
+	playerOnline.DeletePartialMatch(labels)
+	playerHealth.DeletePartialMatch(labels)
+	playerPosition.DeletePartialMatch(labels)
+	playerInventoryItems.DeletePartialMatch(labels)
+	playerEquipment.DeletePartialMatch(labels)
+	gameStatistics.DeletePartialMatch(labels)
+	buildingsTotal.DeletePartialMatch(labels)
+	centralStorageItems.DeletePartialMatch(labels)
+	centralStorageItemLimit.DeletePartialMatch(labels)
+	centralStorageFillPercent.DeletePartialMatch(labels)
+	centralStorageUploaders.DeletePartialMatch(labels)
+	droppedProperties.DeletePartialMatch(labels)
+	unknownProperties.DeletePartialMatch(labels)
+	saveTimestamp.DeletePartialMatch(labels)
+	saveAge.DeletePartialMatch(labels)
+	playDuration.DeletePartialMatch(labels)
+	levelObjects.DeletePartialMatch(labels)
+	jsonSize.DeletePartialMatch(labels)
+	loadAllocated.DeletePartialMatch(labels)
 }
@@ -0,0 +1,76 @@
+package metrics
+
+import (
+	"log"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// Parser diagnostics metrics
+	droppedProperties = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "savefile_dropped_properties",
+			Help: "Number of properties dropped while decoding the save file, by property type",
+		},
+		sessionLabels("type"),
+	)
+
+	unknownProperties = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "savefile_unknown_properties",
+			Help: "Number of properties with an unknown type kept as raw JSON, by property type",
+		},
+		sessionLabels("type"),
+	)
+
+	levelObjects = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "savefile_level_objects",
+			Help: "Number of objects in each level of the save file",
+		},
+		sessionLabels("level"),
+	)
+
+	jsonSize = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "savefile_json_size_bytes",
+			Help: "Size of the intermediate JSON file the save was decoded from",
+		},
+		sessionLabels(),
+	)
+
+	loadAllocated = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "savefile_load_allocated_bytes",
+			Help: "Approximate bytes allocated by the process while unmarshaling and indexing the save file",
+		},
+		sessionLabels(),
+	)
+)
+
+// updateParseMetrics reports properties the decoder could not handle and the
+// size of what was decoded
+func (mc *MetricsCollector) updateParseMetrics() {
+	for level, count := range mc.saveFile.ObjectsPerLevel() {
+		mc.gauge(levelObjects).WithLabelValues(level).Set(float64(count))
+	}
+	stats := mc.saveFile.LoadStats()
+	mc.gauge(jsonSize).WithLabelValues().Set(float64(stats.JSONSize))
+	mc.gauge(loadAllocated).WithLabelValues().Set(float64(stats.Allocated))
+
+	dropped := 0
+	for _, diag := range mc.saveFile.Diagnostics() {
+		if diag.Dropped {
+			mc.gauge(droppedProperties).WithLabelValues(diag.Type).Inc()
+			dropped++
+		} else {
+			mc.gauge(unknownProperties).WithLabelValues(diag.Type).Inc()
+		}
+	}
+
+	if dropped > 0 {
+		log.Printf("Warning: %d properties were dropped while decoding, see /debug/parse", dropped)
+	}
+}
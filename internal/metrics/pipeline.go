@@ -0,0 +1,63 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/FreekingDean/satisfactory-buddy/internal/savefile"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Parse phases reported by parse_phase_duration_seconds
+const (
+	PhaseDecode    = "decode"
+	PhaseUnmarshal = "unmarshal"
+	PhaseIndex     = "index"
+	PhaseCollect   = "collect"
+)
+
+var (
+	// Exporter pipeline metrics. Like the load loop metrics these accumulate
+	// across updates and are only removed when the session is forgotten.
+	parsePhaseDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "parse_phase_duration_seconds",
+			Help:    "Time spent loading a save, by phase: decode (.sav to JSON), unmarshal, index and collect",
+			Buckets: prometheus.ExponentialBuckets(0.01, 2, 14),
+		},
+		sessionLabels("phase"),
+	)
+
+	collectorDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "collector_duration_seconds",
+			Help:    "Time spent updating the metrics of a collector",
+			Buckets: prometheus.ExponentialBuckets(0.0005, 2, 14),
+		},
+		sessionLabels("collector"),
+	)
+
+	collectorErrors = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "collector_errors_total",
+			Help: "Number of times a collector failed while updating metrics",
+		},
+		sessionLabels("collector"),
+	)
+)
+
+// ObserveLoad records how long each phase of loading a session's save took
+func ObserveLoad(server, session string, stats savefile.LoadStats) {
+	phases := parsePhaseDuration.MustCurryWith(prometheus.Labels{"server": server, "session": session})
+	phases.WithLabelValues(PhaseDecode).Observe(stats.Decode.Seconds())
+	phases.WithLabelValues(PhaseUnmarshal).Observe(stats.Unmarshal.Seconds())
+	phases.WithLabelValues(PhaseIndex).Observe(stats.Index.Seconds())
+}
+
+// observeCollector records the duration and failure of one collector run
+func (mc *MetricsCollector) observeCollector(name string, duration time.Duration, err error) {
+	mc.histogram(collectorDuration).WithLabelValues(name).Observe(duration.Seconds())
+	if err != nil {
+		mc.counter(collectorErrors).WithLabelValues(name).Inc()
+	}
+}
@@ -0,0 +1,77 @@
+package metrics
+
+import (
+	"log"
+
+	"github.com/FreekingDean/satisfactory-buddy/internal/players"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// Player metrics
+	playerOnline = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "player_online",
+			Help: "Whether the player was connected when the game was saved",
+		},
+		sessionLabels("player_id", "player_name"),
+	)
+
+	playerHealth = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "player_health",
+			Help: "Current player health",
+		},
+		sessionLabels("player_id", "player_name"),
+	)
+
+	playerPosition = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "player_position",
+			Help: "Last known player position in world units",
+		},
+		sessionLabels("player_id", "player_name", "axis"),
+	)
+
+	playerInventoryItems = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "player_inventory_items",
+			Help: "Number of items carried in the player inventory",
+		},
+		sessionLabels("player_id", "player_name", "item", "item_name"),
+	)
+
+	playerEquipment = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "player_equipment_items",
+			Help: "Number of items in the player equipment slots",
+		},
+		sessionLabels("player_id", "player_name", "item", "item_name"),
+	)
+)
+
+// updatePlayerMetrics reports position, health and inventory for every player
+func (mc *MetricsCollector) updatePlayerMetrics() {
+	all := players.Players(mc.saveFile)
+	for _, player := range all {
+		online := 0.0
+		if player.Online {
+			online = 1
+		}
+		mc.gauge(playerOnline).WithLabelValues(player.ID, player.Name).Set(online)
+		mc.gauge(playerHealth).WithLabelValues(player.ID, player.Name).Set(player.Health)
+		mc.gauge(playerPosition).WithLabelValues(player.ID, player.Name, "x").Set(player.Position.X)
+		mc.gauge(playerPosition).WithLabelValues(player.ID, player.Name, "y").Set(player.Position.Y)
+		mc.gauge(playerPosition).WithLabelValues(player.ID, player.Name, "z").Set(player.Position.Z)
+
+		for _, stack := range player.Inventory {
+			mc.gauge(playerInventoryItems).WithLabelValues(player.ID, player.Name, stack.Item, stack.ItemName).Add(float64(stack.Count))
+		}
+		for _, stack := range player.Equipment {
+			mc.gauge(playerEquipment).WithLabelValues(player.ID, player.Name, stack.Item, stack.ItemName).Add(float64(stack.Count))
+		}
+	}
+
+	log.Printf("Updated player metrics for %d players", len(all))
+}
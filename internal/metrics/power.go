@@ -3,8 +3,8 @@ package metrics
 import (
 	"log"
 	"strconv"
-	"strings"
 
+	"github.com/FreekingDean/satisfactory-buddy/internal/catalog"
 	"github.com/FreekingDean/satisfactory-buddy/internal/savefile"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
@@ -17,7 +17,7 @@ var (
 			Name: "power_generation_mw",
 			Help: "Current power generation in MW",
 		},
-		[]string{"circuit", "building_id", "building_type", "building_name", "potential"},
+		sessionLabels("circuit", "building_id", "building_type", "building_name", "potential"),
 	)
 
 	// Power consumption metrics
@@ -26,7 +26,7 @@ var (
 			Name: "power_consumption_mw",
 			Help: "Current power consumption in MW",
 		},
-		[]string{"circuit", "building_id", "building_type", "building_name", "potential"},
+		sessionLabels("circuit", "building_id", "building_type", "building_name", "potential"),
 	)
 
 	// Power capacity metrics
@@ -35,17 +35,14 @@ var (
 			Name: "power_max_consumption_mw",
 			Help: "Maximum power consumption capacity in MW",
 		},
-		[]string{"circuit", "building_id", "building_type", "building_name", "potential"},
+		sessionLabels("circuit", "building_id", "building_type", "building_name", "potential"),
 	)
 )
 
 // updatePowerMetrics collects power generation and consumption data
 func (mc *MetricsCollector) updatePowerMetrics() {
-	for _, obj := range mc.saveFile.AllGameObjects() {
-
-		if strings.HasSuffix(obj.TypePath, "FGPowerInfoComponent") {
-			mc.updatePowerMetric(obj)
-		}
+	for _, obj := range mc.saveFile.ObjectsOfType("FGPowerInfoComponent") {
+		mc.updatePowerMetric(obj)
 	}
 
 	log.Printf("Updated power metrics for save file")
@@ -70,17 +67,18 @@ func (mc *MetricsCollector) updatePowerMetric(obj *savefile.GameObject) {
 	buildingType := humanBuildingType(parent.SimpleType())
 	buildingID := parent.Instance()
 	potential := "hi"
-	if buildingType == "Biomass Generator" || buildingType == "Fuel Generator" {
-		amount := 0.0
-		if val, ok := obj.Properties.FloatProperties["mDynamicProductionCapacity"]; ok {
-			amount = val.Value
+	if catalog.IsGenerator(parent.SimpleType()) {
+		amount, err := obj.GetFloatOr("mDynamicProductionCapacity", 0)
+		if err != nil {
+			log.Printf("Warning: %v", err)
+			return
 		}
-		powerGeneration.WithLabelValues(circuit, buildingID, buildingType, buildingType, potential).Set(amount)
+		mc.gauge(powerGeneration).WithLabelValues(circuit, buildingID, buildingType, buildingType, potential).Set(amount)
 		return
 	}
 
-	if consumption, ok := obj.Properties.FloatProperties["mTargetConsumption"]; ok {
-		powerConsumption.WithLabelValues(circuit, buildingID, buildingType, buildingType, potential).Set(consumption.Value)
+	if consumption, err := obj.GetFloat("mTargetConsumption"); err == nil {
+		mc.gauge(powerConsumption).WithLabelValues(circuit, buildingID, buildingType, buildingType, potential).Set(consumption)
 	}
 }
 
@@ -0,0 +1,11 @@
+package metrics
+
+import (
+	"github.com/FreekingDean/satisfactory-buddy/internal/catalog"
+)
+
+// productionRates sums the per-minute output of every manufacturer running a
+// recipe known to the catalog, keyed by item class
+func (mc *MetricsCollector) productionRates() map[string]float64 {
+	return catalog.ProductionRates(mc.saveFile)
+}
@@ -0,0 +1,46 @@
+package metrics
+
+import (
+	"log"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// Save freshness metrics
+	saveTimestamp = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "save_timestamp_seconds",
+			Help: "Unix time the save file was written by the game",
+		},
+		sessionLabels(),
+	)
+
+	saveAge = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "save_age_seconds",
+			Help: "Seconds between the save file being written and the last metrics update",
+		},
+		sessionLabels(),
+	)
+
+	playDuration = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "play_duration_seconds",
+			Help: "Total play time recorded in the save file",
+		},
+		sessionLabels(),
+	)
+)
+
+// updateSaveMetrics reports when the save was written and how much play time it holds
+func (mc *MetricsCollector) updateSaveMetrics() {
+	header := mc.saveFile.Header
+	mc.gauge(saveTimestamp).WithLabelValues().Set(float64(header.SaveDateTime.Unix()))
+	mc.gauge(saveAge).WithLabelValues().Set(time.Since(header.SaveDateTime.Time).Seconds())
+	mc.gauge(playDuration).WithLabelValues().Set(float64(header.PlayDurationSeconds))
+
+	log.Printf("Updated save metrics for save written %s", header.SaveDateTime.Format(time.RFC3339))
+}
@@ -0,0 +1,184 @@
+package metrics
+
+import (
+	"log"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/FreekingDean/satisfactory-buddy/internal/catalog"
+	"github.com/FreekingDean/satisfactory-buddy/internal/savefile"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// Space elevator phase metrics
+	spaceElevatorPhase = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "space_elevator_phase",
+			Help: "Current space elevator phase (0 before the first delivery)",
+		},
+		sessionLabels("phase_name"),
+	)
+
+	spaceElevatorPartRequired = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "space_elevator_part_required",
+			Help: "Parts required to complete the space elevator phase being delivered",
+		},
+		sessionLabels("phase", "item", "item_name"),
+	)
+
+	spaceElevatorPartDelivered = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "space_elevator_part_delivered",
+			Help: "Parts delivered towards the space elevator phase being delivered",
+		},
+		sessionLabels("phase", "item", "item_name"),
+	)
+
+	spaceElevatorPartRemaining = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "space_elevator_part_remaining",
+			Help: "Parts still missing for the space elevator phase being delivered",
+		},
+		sessionLabels("phase", "item", "item_name"),
+	)
+
+	spaceElevatorPartProduction = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "space_elevator_part_production_per_minute",
+			Help: "Factory-wide production rate of a space elevator part per minute",
+		},
+		sessionLabels("phase", "item", "item_name"),
+	)
+
+	spaceElevatorPartETA = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "space_elevator_part_eta_seconds",
+			Help: "Estimated seconds until enough of a part is produced for the current phase",
+		},
+		sessionLabels("phase", "item", "item_name"),
+	)
+
+	spaceElevatorPhaseETA = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "space_elevator_phase_eta_seconds",
+			Help: "Estimated seconds until every part for the current phase is produced",
+		},
+		sessionLabels("phase"),
+	)
+)
+
+// spaceElevatorTypes are the objects that may carry the project assembly
+// state, most authoritative first. Several of them can hold the same paid off
+// costs, so deliveries are read from the first one that has them.
+var spaceElevatorTypes = []string{
+	"BP_GamePhaseManager_C",
+	"BP_ProjectAssembly_C",
+	"Build_SpaceElevator_C",
+}
+
+// updateSpaceElevatorMetrics reports the space elevator phase and part deficits
+func (mc *MetricsCollector) updateSpaceElevatorMetrics() {
+	var phaseName string
+	targetPhase := -1
+	var delivered map[string]int
+
+	var objects []*savefile.GameObject
+	for _, t := range spaceElevatorTypes {
+		objects = append(objects, mc.saveFile.ObjectsOfType(t)...)
+	}
+
+	for _, obj := range objects {
+		if current, err := obj.GetObjectRef("mCurrentGamePhase"); err == nil && phaseName == "" {
+			phaseName = catalog.ClassName(current.PathName)
+		}
+		if target, err := obj.GetObjectRef("mTargetGamePhase"); err == nil && targetPhase < 0 {
+			targetPhase = gamePhaseNumber(catalog.ClassName(target.PathName))
+		}
+		if costs, err := savefile.StructArrayAs[savefile.ItemAmount](obj.Properties, "mTargetGamePhasePaidOffCosts"); costs != nil && delivered == nil {
+			if err != nil {
+				log.Printf("Warning: %v", err)
+			}
+			delivered = itemAmounts(costs)
+		}
+	}
+
+	if phaseName == "" {
+		log.Printf("Warning: No space elevator phase found in save file")
+		return
+	}
+
+	phase := gamePhaseNumber(phaseName)
+	mc.gauge(spaceElevatorPhase).WithLabelValues(phaseName).Set(float64(phase))
+	if targetPhase < 0 {
+		targetPhase = phase + 1
+	}
+
+	required, ok := catalog.ElevatorPhases[targetPhase]
+	if !ok {
+		log.Printf("Updated space elevator metrics: no further phases after %d", phase)
+		return
+	}
+
+	rates := mc.productionRates()
+	phaseLabel := strconv.Itoa(targetPhase)
+	phaseETA := 0.0
+	for item, count := range required {
+		itemName := catalog.ItemName(item)
+		remaining := max(count-delivered[item], 0)
+
+		mc.gauge(spaceElevatorPartRequired).WithLabelValues(phaseLabel, item, itemName).Set(float64(count))
+		mc.gauge(spaceElevatorPartDelivered).WithLabelValues(phaseLabel, item, itemName).Set(float64(delivered[item]))
+		mc.gauge(spaceElevatorPartRemaining).WithLabelValues(phaseLabel, item, itemName).Set(float64(remaining))
+
+		rate, known := rates[item]
+		mc.gauge(spaceElevatorPartProduction).WithLabelValues(phaseLabel, item, itemName).Set(rate)
+		if remaining == 0 {
+			mc.gauge(spaceElevatorPartETA).WithLabelValues(phaseLabel, item, itemName).Set(0)
+			continue
+		}
+		if !known || rate <= 0 {
+			phaseETA = math.Inf(1)
+			continue
+		}
+
+		eta := float64(remaining) / rate * 60
+		mc.gauge(spaceElevatorPartETA).WithLabelValues(phaseLabel, item, itemName).Set(eta)
+		phaseETA = math.Max(phaseETA, eta)
+	}
+
+	if !math.IsInf(phaseETA, 1) {
+		mc.gauge(spaceElevatorPhaseETA).WithLabelValues(phaseLabel).Set(phaseETA)
+	}
+
+	log.Printf("Updated space elevator metrics for phase %d", targetPhase)
+}
+
+// gamePhaseNumber extracts the phase number from names like "GP_Project_Assembly_Phase_2"
+func gamePhaseNumber(name string) int {
+	name = strings.TrimSuffix(name, "_C")
+	idx := strings.LastIndex(name, "_")
+	if idx < 0 {
+		return 0
+	}
+	phase, err := strconv.Atoi(name[idx+1:])
+	if err != nil {
+		return 0
+	}
+	return phase
+}
+
+// itemAmounts sums ItemAmount structs into counts keyed by item class
+func itemAmounts(values []savefile.ItemAmount) map[string]int {
+	amounts := make(map[string]int)
+	for _, value := range values {
+		if value.ItemClass == "" {
+			continue
+		}
+		amounts[catalog.ClassName(value.ItemClass)] += value.Amount
+	}
+	return amounts
+}
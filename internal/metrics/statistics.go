@@ -0,0 +1,146 @@
+package metrics
+
+import (
+	"log"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/FreekingDean/satisfactory-buddy/internal/catalog"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// gameStatistics exports the lifetime game statistics. They are cumulative
+// totals kept by the save itself, so they are exported as counters set from
+// each save rather than counted by the exporter; graph them with rate()
+var gameStatistics = newStatisticsCollector()
+
+func init() {
+	prometheus.MustRegister(gameStatistics)
+}
+
+// statisticKey identifies one statistic series of a session
+type statisticKey struct {
+	server, session, statistic, class string
+}
+
+// statisticsCollector holds the statistics of every session and exports them
+// as const counters, since a CounterVec can only be incremented
+type statisticsCollector struct {
+	desc *prometheus.Desc
+
+	mu     sync.RWMutex
+	counts map[statisticKey]float64
+}
+
+func newStatisticsCollector() *statisticsCollector {
+	return &statisticsCollector{
+		desc: prometheus.NewDesc(
+			"game_statistics_total",
+			"Lifetime game statistics from FGStatisticsSubsystem by item or building class",
+			sessionLabels("statistic", "class", "class_name"), nil,
+		),
+		counts: make(map[statisticKey]float64),
+	}
+}
+
+// Describe implements prometheus.Collector
+func (c *statisticsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+// Collect implements prometheus.Collector
+func (c *statisticsCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for key, count := range c.counts {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.CounterValue, count,
+			key.server, key.session, key.statistic, key.class, catalog.ItemName(key.class))
+	}
+}
+
+// set replaces the value of one statistic for the session in labels
+func (c *statisticsCollector) set(labels prometheus.Labels, statistic, class string, count float64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.counts[statisticKey{labels["server"], labels["session"], statistic, class}] = count
+}
+
+// DeletePartialMatch removes every statistic of the session in labels
+func (c *statisticsCollector) DeletePartialMatch(labels prometheus.Labels) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	deleted := 0
+	for key := range c.counts {
+		if key.server == labels["server"] && key.session == labels["session"] {
+			delete(c.counts, key)
+			deleted++
+		}
+	}
+	return deleted
+}
+
+// updateStatisticsMetrics decodes the statistics subsystem maps into counters
+func (mc *MetricsCollector) updateStatisticsMetrics() {
+	for _, obj := range mc.saveFile.ObjectsOfType("FGStatisticsSubsystem") {
+		for name, prop := range obj.Properties.MapProperties {
+			statistic := statisticName(name)
+			for _, entry := range prop.Values {
+				class := catalog.ClassName(mapKeyPath(entry["key"]))
+				count, ok := mapNumber(entry["value"])
+				if class == "" || !ok || count < 0 {
+					continue
+				}
+				gameStatistics.set(mc.labels, statistic, class, count)
+			}
+		}
+	}
+
+	log.Printf("Updated statistics metrics for save file")
+}
+
+// statisticName turns a property such as "mItemsManuallyCraftedCount" into
+// "items_manually_crafted"
+func statisticName(property string) string {
+	property = strings.TrimPrefix(property, "m")
+	property = strings.TrimSuffix(property, "Count")
+
+	var b strings.Builder
+	for i, r := range property {
+		if unicode.IsUpper(r) && i > 0 {
+			b.WriteByte('_')
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+// mapKeyPath reads the object path from a map key, which is either a bare
+// path or an object reference
+func mapKeyPath(key interface{}) string {
+	switch k := key.(type) {
+	case string:
+		return k
+	case map[string]interface{}:
+		if path, ok := k["pathName"].(string); ok {
+			return path
+		}
+		if value, ok := k["value"]; ok {
+			return mapKeyPath(value)
+		}
+	}
+	return ""
+}
+
+// mapNumber reads a numeric map value, which is either bare or wrapped in a property
+func mapNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case map[string]interface{}:
+		if inner, ok := v["value"]; ok {
+			return mapNumber(inner)
+		}
+	}
+	return 0, false
+}
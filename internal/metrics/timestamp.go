@@ -0,0 +1,83 @@
+package metrics
+
+import (
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// scrapeTimeMetrics describe the exporter rather than the save and always
+// keep their scrape time
+var scrapeTimeMetrics = map[string]bool{
+	"save_age_seconds":                true,
+	"parse_errors_total":              true,
+	"last_successful_parse_timestamp": true,
+	"parse_phase_duration_seconds":    true,
+	"collector_duration_seconds":      true,
+	"collector_errors_total":          true,
+	"alerts_firing":                   true,
+}
+
+// scrapeTimePrefixes are the runtime and HTTP metrics registered by client_golang
+var scrapeTimePrefixes = []string{"go_", "process_", "promhttp_"}
+
+// maxSampleAge is how far back Prometheus accepts samples into its head
+// block; older saves keep the scrape time instead
+const maxSampleAge = time.Hour
+
+// timestampGatherer stamps save-derived samples with the time the save was written
+type timestampGatherer struct {
+	gatherer prometheus.Gatherer
+	saveTime func(server, session string) time.Time
+}
+
+// NewTimestampGatherer wraps gatherer so every save-derived sample carries the
+// time saveTime returns for its server and session instead of the scrape time.
+// Prometheus rejects samples older than its head block, so samples of saves
+// more than an hour old are left at the scrape time.
+func NewTimestampGatherer(gatherer prometheus.Gatherer, saveTime func(server, session string) time.Time) prometheus.Gatherer {
+	return &timestampGatherer{gatherer: gatherer, saveTime: saveTime}
+}
+
+// Gather implements prometheus.Gatherer
+func (g *timestampGatherer) Gather() ([]*dto.MetricFamily, error) {
+	families, err := g.gatherer.Gather()
+	for _, family := range families {
+		if isScrapeTimeMetric(family.GetName()) {
+			continue
+		}
+		for _, metric := range family.Metric {
+			var server, session string
+			for _, label := range metric.Label {
+				switch label.GetName() {
+				case "server":
+					server = label.GetValue()
+				case "session":
+					session = label.GetValue()
+				}
+			}
+
+			saveTime := g.saveTime(server, session)
+			if saveTime.IsZero() || time.Since(saveTime) > maxSampleAge {
+				continue
+			}
+			timestamp := saveTime.UnixMilli()
+			metric.TimestampMs = &timestamp
+		}
+	}
+	return families, err
+}
+
+func isScrapeTimeMetric(name string) bool {
+	if scrapeTimeMetrics[name] {
+		return true
+	}
+	for _, prefix := range scrapeTimePrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
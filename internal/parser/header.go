@@ -0,0 +1,99 @@
+package parser
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"time"
+	"unicode/utf16"
+)
+
+// ticksAtUnixEpoch is 1970-01-01 in .NET ticks of 100ns since 0001-01-01,
+// the format the game stores save times in
+const ticksAtUnixEpoch = 621355968000000000
+
+// maxStringLength guards against reading garbage as a huge string length
+const maxStringLength = 1 << 16
+
+// Header is the uncompressed start of a .sav file, read without running the
+// full parser to decide which saves to parse
+type Header struct {
+	SaveHeaderType      int32
+	SaveVersion         int32
+	BuildVersion        int32
+	SaveName            string
+	MapName             string
+	MapOptions          string
+	SessionName         string
+	PlayDurationSeconds int32
+	SaveDateTime        time.Time
+}
+
+// ReadHeader reads the header of the .sav file at path
+func ReadHeader(path string) (Header, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return Header{}, fmt.Errorf("failed to open save file: %w", err)
+	}
+	defer file.Close()
+
+	r := bufio.NewReader(file)
+	var header Header
+	for _, field := range []*int32{&header.SaveHeaderType, &header.SaveVersion, &header.BuildVersion} {
+		if err := binary.Read(r, binary.LittleEndian, field); err != nil {
+			return header, fmt.Errorf("failed to read save header: %w", err)
+		}
+	}
+
+	fields := []*string{&header.MapName, &header.MapOptions, &header.SessionName}
+	// The save name was added in header version 14
+	if header.SaveHeaderType >= 14 {
+		fields = append([]*string{&header.SaveName}, fields...)
+	}
+	for _, field := range fields {
+		if *field, err = readString(r); err != nil {
+			return header, fmt.Errorf("failed to read save header: %w", err)
+		}
+	}
+
+	if err := binary.Read(r, binary.LittleEndian, &header.PlayDurationSeconds); err != nil {
+		return header, fmt.Errorf("failed to read save header: %w", err)
+	}
+	var ticks int64
+	if err := binary.Read(r, binary.LittleEndian, &ticks); err != nil {
+		return header, fmt.Errorf("failed to read save header: %w", err)
+	}
+	header.SaveDateTime = time.Unix(0, (ticks-ticksAtUnixEpoch)*100)
+
+	return header, nil
+}
+
+// readString reads an Unreal FString: a length that is negative for UTF-16
+// text, followed by the characters and a null terminator
+func readString(r io.Reader) (string, error) {
+	var length int32
+	if err := binary.Read(r, binary.LittleEndian, &length); err != nil {
+		return "", err
+	}
+
+	switch {
+	case length == 0:
+		return "", nil
+	case length > maxStringLength || length < -maxStringLength:
+		return "", fmt.Errorf("invalid string length %d", length)
+	case length > 0:
+		buf := make([]byte, length)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return "", err
+		}
+		return string(buf[:length-1]), nil
+	default:
+		buf := make([]uint16, -length)
+		if err := binary.Read(r, binary.LittleEndian, buf); err != nil {
+			return "", err
+		}
+		return string(utf16.Decode(buf[:len(buf)-1])), nil
+	}
+}
@@ -0,0 +1,148 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+	"unicode/utf16"
+)
+
+// headerBytes encodes a save header the way the game writes it
+type headerBytes struct {
+	bytes.Buffer
+}
+
+func (b *headerBytes) int32(v int32) *headerBytes {
+	binary.Write(&b.Buffer, binary.LittleEndian, v)
+	return b
+}
+
+func (b *headerBytes) int64(v int64) *headerBytes {
+	binary.Write(&b.Buffer, binary.LittleEndian, v)
+	return b
+}
+
+func (b *headerBytes) str(s string) *headerBytes {
+	if s == "" {
+		return b.int32(0)
+	}
+	b.int32(int32(len(s) + 1))
+	b.WriteString(s)
+	b.WriteByte(0)
+	return b
+}
+
+func (b *headerBytes) utf16(s string) *headerBytes {
+	units := append(utf16.Encode([]rune(s)), 0)
+	b.int32(-int32(len(units)))
+	binary.Write(&b.Buffer, binary.LittleEndian, units)
+	return b
+}
+
+func TestReadHeader(t *testing.T) {
+	saveTime := time.Date(2024, 3, 1, 18, 30, 0, 0, time.UTC)
+	ticks := saveTime.UnixNano()/100 + ticksAtUnixEpoch
+
+	tests := []struct {
+		name    string
+		data    []byte
+		want    Header
+		wantErr bool
+	}{
+		{
+			name: "version 14 with save name",
+			data: new(headerBytes).int32(14).int32(52).int32(424353).
+				str("Factory_autosave_0").str("Persistent_Level").str("?opts").str("Factory").
+				int32(3600).int64(ticks).Bytes(),
+			want: Header{
+				SaveHeaderType: 14, SaveVersion: 52, BuildVersion: 424353,
+				SaveName: "Factory_autosave_0", MapName: "Persistent_Level", MapOptions: "?opts", SessionName: "Factory",
+				PlayDurationSeconds: 3600, SaveDateTime: saveTime,
+			},
+		},
+		{
+			name: "version 13 without save name",
+			data: new(headerBytes).int32(13).int32(46).int32(211839).
+				str("Persistent_Level").str("").str("Old").
+				int32(60).int64(ticks).Bytes(),
+			want: Header{
+				SaveHeaderType: 13, SaveVersion: 46, BuildVersion: 211839,
+				MapName: "Persistent_Level", SessionName: "Old",
+				PlayDurationSeconds: 60, SaveDateTime: saveTime,
+			},
+		},
+		{
+			name: "utf-16 session name",
+			data: new(headerBytes).int32(14).int32(52).int32(1).
+				str("save").str("map").str("").utf16("Fabrik Größe").
+				int32(1).int64(ticks).Bytes(),
+			want: Header{
+				SaveHeaderType: 14, SaveVersion: 52, BuildVersion: 1,
+				SaveName: "save", MapName: "map", SessionName: "Fabrik Größe",
+				PlayDurationSeconds: 1, SaveDateTime: saveTime,
+			},
+		},
+		{
+			name:    "truncated",
+			data:    new(headerBytes).int32(14).int32(52).Bytes(),
+			wantErr: true,
+		},
+		{
+			name:    "truncated string",
+			data:    new(headerBytes).int32(14).int32(52).int32(1).int32(20).Bytes(),
+			wantErr: true,
+		},
+		{
+			name:    "invalid string length",
+			data:    new(headerBytes).int32(14).int32(52).int32(1).int32(1 << 20).Bytes(),
+			wantErr: true,
+		},
+		{
+			name:    "empty file",
+			data:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "test.sav")
+			if err := os.WriteFile(path, tt.data, 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := ReadHeader(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ReadHeader() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			got.SaveDateTime = got.SaveDateTime.UTC()
+			if got != tt.want {
+				t.Errorf("ReadHeader() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadHeaderFixture(t *testing.T) {
+	header, err := ReadHeader("../../test/fixtures/map.sav")
+	if err != nil {
+		t.Fatalf("ReadHeader() error = %v", err)
+	}
+	if header.SessionName != "TheMayonasining" || header.SaveName != "TheMayonasining_autosave_0" {
+		t.Errorf("ReadHeader() session %q save %q, want TheMayonasining and TheMayonasining_autosave_0", header.SessionName, header.SaveName)
+	}
+	if want := time.Date(2025, 8, 24, 21, 25, 44, 392000000, time.UTC); !header.SaveDateTime.Equal(want) {
+		t.Errorf("ReadHeader() save time %s, want %s", header.SaveDateTime.UTC(), want)
+	}
+}
+
+func TestReadHeaderMissingFile(t *testing.T) {
+	if _, err := ReadHeader(filepath.Join(t.TempDir(), "missing.sav")); err == nil {
+		t.Error("ReadHeader() of a missing file succeeded")
+	}
+}
@@ -8,12 +8,25 @@ import (
 	"os"
 	"os/exec"
 	"path"
+	"runtime/metrics"
+	"time"
 
 	"github.com/FreekingDean/satisfactory-buddy/internal/savefile"
 )
 
-func Parse(mapPath string, jsonPath string) (savefile.SaveFile, error) {
+// Backend names the tool that converts .sav files to JSON
+const Backend = "@etothepii/satisfactory-file-parser"
+
+// Options configures how a save file is parsed
+type Options struct {
+	// Strict fails the parse when any property could not be decoded or has
+	// an unknown type
+	Strict bool
+}
+
+func Parse(mapPath string, jsonPath string, opts Options) (savefile.SaveFile, error) {
 	var saveFile savefile.SaveFile
+	saveFile.SetStrict(opts.Strict)
 	filename := path.Base(mapPath)
 	jsonFilename := path.Join(jsonPath, filename+".json")
 	cmd := exec.Command("npm", "run", "parse", mapPath, jsonFilename)
@@ -22,11 +35,25 @@ func Parse(mapPath string, jsonPath string) (savefile.SaveFile, error) {
 	cmd.Stdout = buffer
 	errBuffer := new(bytes.Buffer)
 	cmd.Stderr = errBuffer
+	start := time.Now()
 	err := cmd.Run()
 
 	if cmd.ProcessState.ExitCode() != 0 || err != nil {
 		return saveFile, fmt.Errorf("failed to parse save file (%s): \nstderr:\n%s\nstdout:\n%s", err.Error(), errBuffer.String(), buffer.String())
 	}
+	decode := time.Since(start)
+
+	saveFile, err = Load(jsonFilename, opts)
+	stats := saveFile.LoadStats()
+	stats.Decode = decode
+	saveFile.SetLoadStats(stats)
+	return saveFile, err
+}
+
+// Load decodes a save file previously converted to JSON
+func Load(jsonFilename string, opts Options) (savefile.SaveFile, error) {
+	var saveFile savefile.SaveFile
+	saveFile.SetStrict(opts.Strict)
 
 	file, err := os.Open(jsonFilename)
 	if err != nil {
@@ -34,7 +61,36 @@ func Parse(mapPath string, jsonPath string) (savefile.SaveFile, error) {
 	}
 	defer file.Close()
 
+	info, err := file.Stat()
+	if err != nil {
+		return saveFile, fmt.Errorf("failed to stat save file: %w", err)
+	}
+
 	log.Println("Loading save file...")
+	before := allocatedBytes()
+	start := time.Now()
 	decoder := json.NewDecoder(file)
-	return saveFile, decoder.Decode(&saveFile)
+	err = decoder.Decode(&saveFile)
+	allocated := allocatedBytes() - before
+
+	// Indexing happens inside UnmarshalJSON, so it is taken out of the total
+	stats := saveFile.LoadStats()
+	stats.Unmarshal = time.Since(start) - stats.Index
+	stats.JSONSize = info.Size()
+	stats.Allocated = allocated
+	saveFile.SetLoadStats(stats)
+	return saveFile, err
+}
+
+// allocatedBytes returns the bytes allocated by the whole process so far.
+// Unlike runtime.ReadMemStats it does not stop the world, but it is only
+// updated as goroutines flush their allocation caches, so differences are
+// approximate and include allocations made concurrently by other goroutines.
+func allocatedBytes() uint64 {
+	sample := []metrics.Sample{{Name: "/gc/heap/allocs:bytes"}}
+	metrics.Read(sample)
+	if sample[0].Value.Kind() != metrics.KindUint64 {
+		return 0
+	}
+	return sample[0].Value.Uint64()
 }
@@ -0,0 +1,140 @@
+package players
+
+import (
+	"sort"
+
+	"github.com/FreekingDean/satisfactory-buddy/internal/catalog"
+	"github.com/FreekingDean/satisfactory-buddy/internal/savefile"
+)
+
+// defaultHealth is the player health the game omits from saves when unchanged
+const defaultHealth = 100.0
+
+// Player is the decoded state of one player in the save
+type Player struct {
+	ID        string            `json:"id"`
+	Name      string            `json:"name"`
+	Online    bool              `json:"online"`
+	Position  savefile.Vector3D `json:"position"`
+	Health    float64           `json:"health"`
+	Dead      bool              `json:"dead"`
+	Equipment []ItemStack       `json:"equipment"`
+	Inventory []ItemStack       `json:"inventory"`
+	Hotbars   [][]string        `json:"hotbars"`
+}
+
+// ItemStack is a number of items in a single inventory slot
+type ItemStack struct {
+	Item     string `json:"item"`
+	ItemName string `json:"itemName"`
+	Count    int    `json:"count"`
+}
+
+// Players decodes every BP_PlayerState and its Char_Player pawn
+func Players(sf *savefile.SaveFile) []Player {
+	var result []Player
+	for _, state := range sf.ObjectsOfType("BP_PlayerState_C") {
+		player := Player{
+			ID:     state.Instance(),
+			Name:   playerName(state),
+			Health: defaultHealth,
+		}
+
+		if pawnRef, err := state.GetObjectRef("mOwnedPawn"); err == nil {
+			if pawn := sf.GetGameObject(pawnRef.PathName); pawn != nil {
+				decodePawn(sf, pawn, &player)
+			}
+		}
+
+		if hotbars, err := state.GetObjectRefs("mPlayerHotbars"); err == nil {
+			for _, ref := range hotbars {
+				if hotbar := sf.GetGameObject(ref.PathName); hotbar != nil {
+					player.Hotbars = append(player.Hotbars, hotbarRecipes(sf, hotbar))
+				}
+			}
+		}
+
+		result = append(result, player)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ID < result[j].ID
+	})
+	return result
+}
+
+func playerName(state *savefile.GameObject) string {
+	for _, name := range []string{"mCachedPlayerName", "mPlayerName"} {
+		if playerName, err := state.GetString(name); err == nil && playerName != "" {
+			return playerName
+		}
+	}
+	return state.Instance()
+}
+
+func decodePawn(sf *savefile.SaveFile, pawn *savefile.GameObject, player *Player) {
+	player.Position = pawn.Transform.Translation
+	player.Online, _ = pawn.GetBool("mIsPossessed")
+
+	for _, compRef := range pawn.Components {
+		component := sf.GetGameObject(compRef.PathName)
+		if component == nil {
+			continue
+		}
+
+		switch component.SimpleType() {
+		case "FGHealthComponent":
+			if health, err := component.GetFloatOr("mCurrentHealth", defaultHealth); err == nil {
+				player.Health = health
+			}
+			player.Dead, _ = component.GetBool("mIsDead")
+		case "FGInventoryComponentEquipment":
+			player.Equipment = append(player.Equipment, inventoryStacks(component)...)
+		}
+	}
+
+	if invRef, err := pawn.GetObjectRef("mInventory"); err == nil {
+		if inventory := sf.GetGameObject(invRef.PathName); inventory != nil {
+			player.Inventory = inventoryStacks(inventory)
+		}
+	}
+}
+
+// inventoryStacks decodes the non-empty slots of an inventory component
+func inventoryStacks(inventory *savefile.GameObject) []ItemStack {
+	// Slots that fail to decode are left out rather than hiding the whole
+	// inventory
+	slots, _ := savefile.StructArrayAs[savefile.InventoryStack](inventory.Properties, "mInventoryStacks")
+	if slots == nil {
+		return nil
+	}
+
+	var stacks []ItemStack
+	for _, slot := range slots {
+		item := catalog.ClassName(slot.Item.ItemClass)
+		if item == "" || slot.NumItems == 0 {
+			continue
+		}
+		stacks = append(stacks, ItemStack{Item: item, ItemName: catalog.ItemName(item), Count: slot.NumItems})
+	}
+	return stacks
+}
+
+func hotbarRecipes(sf *savefile.SaveFile, hotbar *savefile.GameObject) []string {
+	shortcuts, err := hotbar.GetObjectRefs("mShortcuts")
+	if err != nil {
+		return nil
+	}
+
+	recipes := make([]string, 0, len(shortcuts))
+	for _, ref := range shortcuts {
+		recipe := ""
+		if shortcut := sf.GetGameObject(ref.PathName); shortcut != nil {
+			if target, err := shortcut.GetObjectRef("mRecipeToActivate"); err == nil {
+				recipe = catalog.ClassName(target.PathName)
+			}
+		}
+		recipes = append(recipes, recipe)
+	}
+	return recipes
+}
@@ -0,0 +1,731 @@
+package savefile
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrPropertyNotFound is returned when a property or path segment does not exist
+	ErrPropertyNotFound = errors.New("property not found")
+	// ErrTypeMismatch is returned when a property exists but holds a different type
+	ErrTypeMismatch = errors.New("property type mismatch")
+)
+
+// PropertyError describes a failed property lookup
+type PropertyError struct {
+	Object string
+	Name   string
+	Want   string
+	Got    string
+	Err    error
+}
+
+func (e *PropertyError) Error() string {
+	var b strings.Builder
+	if e.Object != "" {
+		fmt.Fprintf(&b, "%s: ", e.Object)
+	}
+	fmt.Fprintf(&b, "%s: %v", e.Name, e.Err)
+	if errors.Is(e.Err, ErrTypeMismatch) {
+		fmt.Fprintf(&b, " (want %s, got %s)", e.Want, e.Got)
+	}
+	return b.String()
+}
+
+func (e *PropertyError) Unwrap() error {
+	return e.Err
+}
+
+func notFound(name string) error {
+	return &PropertyError{Name: name, Err: ErrPropertyNotFound}
+}
+
+func mismatch(name, want, got string) error {
+	return &PropertyError{Name: name, Want: want, Got: got, Err: ErrTypeMismatch}
+}
+
+// Type returns the Unreal property type stored under name, or "" if there is none
+func (pc PropertyContainer) Type(name string) string {
+	switch {
+	case has(pc.BoolProperties, name):
+		return "BoolProperty"
+	case has(pc.Int8Properties, name):
+		return "Int8Property"
+	case has(pc.Int32Properties, name):
+		return "Int32Property"
+	case has(pc.Int64Properties, name):
+		return "Int64Property"
+	case has(pc.Uint32Properties, name):
+		return "Uint32Property"
+	case has(pc.Uint64Properties, name):
+		return "Uint64Property"
+	case has(pc.FloatProperties, name):
+		return "FloatProperty"
+	case has(pc.DoubleProperties, name):
+		return "DoubleProperty"
+	case has(pc.StrProperties, name):
+		return "StrProperty"
+	case has(pc.NameProperties, name):
+		return "NameProperty"
+	case has(pc.TextProperties, name):
+		return "TextProperty"
+	case has(pc.ObjectProperties, name):
+		return "ObjectProperty"
+	case has(pc.ObjectArrayProperties, name):
+		return "ObjectArrayProperty"
+	case has(pc.SoftObjectProperties, name):
+		return "SoftObjectProperty"
+	case has(pc.SoftObjectArrayProperties, name):
+		return "SoftObjectArrayProperty"
+	case has(pc.EnumProperties, name):
+		return "EnumProperty"
+	case has(pc.ByteProperties, name):
+		return "ByteProperty"
+	case has(pc.StructProperties, name):
+		return "StructProperty"
+	case has(pc.StructArrayProperties, name):
+		return "StructArrayProperty"
+	case has(pc.Int32ArrayProperties, name):
+		return "Int32ArrayProperty"
+	case has(pc.Int64ArrayProperties, name):
+		return "Int64ArrayProperty"
+	case has(pc.FloatArrayProperties, name):
+		return "FloatArrayProperty"
+	case has(pc.StrArrayProperties, name):
+		return "StrArrayProperty"
+	case has(pc.EnumArrayProperties, name):
+		return "EnumArrayProperty"
+	case has(pc.ByteArrayProperties, name):
+		return "ByteArrayProperty"
+	case has(pc.BoolArrayProperties, name):
+		return "BoolArrayProperty"
+	case has(pc.MapProperties, name):
+		return "MapProperty"
+	case has(pc.Uint32SetProperties, name):
+		return "Uint32SetProperty"
+	case has(pc.SetProperties, name):
+		return "SetProperty"
+	case has(pc.UnknownProperties, name):
+		return "UnknownProperty"
+	default:
+		return ""
+	}
+}
+
+// Has reports whether any property is stored under name
+func (pc PropertyContainer) Has(name string) bool {
+	return pc.Type(name) != ""
+}
+
+// Names returns the names of every property in the container, sorted
+func (pc PropertyContainer) Names() []string {
+	seen := make(map[string]bool)
+	collect := func(names []string) {
+		for _, name := range names {
+			seen[name] = true
+		}
+	}
+	collect(keys(pc.BoolProperties))
+	collect(keys(pc.Int8Properties))
+	collect(keys(pc.Int32Properties))
+	collect(keys(pc.Int64Properties))
+	collect(keys(pc.Uint32Properties))
+	collect(keys(pc.Uint64Properties))
+	collect(keys(pc.FloatProperties))
+	collect(keys(pc.DoubleProperties))
+	collect(keys(pc.StrProperties))
+	collect(keys(pc.NameProperties))
+	collect(keys(pc.TextProperties))
+	collect(keys(pc.ObjectProperties))
+	collect(keys(pc.ObjectArrayProperties))
+	collect(keys(pc.SoftObjectProperties))
+	collect(keys(pc.SoftObjectArrayProperties))
+	collect(keys(pc.EnumProperties))
+	collect(keys(pc.ByteProperties))
+	collect(keys(pc.StructProperties))
+	collect(keys(pc.StructArrayProperties))
+	collect(keys(pc.Int32ArrayProperties))
+	collect(keys(pc.Int64ArrayProperties))
+	collect(keys(pc.FloatArrayProperties))
+	collect(keys(pc.StrArrayProperties))
+	collect(keys(pc.EnumArrayProperties))
+	collect(keys(pc.ByteArrayProperties))
+	collect(keys(pc.BoolArrayProperties))
+	collect(keys(pc.MapProperties))
+	collect(keys(pc.Uint32SetProperties))
+	collect(keys(pc.SetProperties))
+	collect(keys(pc.UnknownProperties))
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func keys[T any](m map[string]T) []string {
+	result := make([]string, 0, len(m))
+	for name := range m {
+		result = append(result, name)
+	}
+	return result
+}
+
+func has[T any](m map[string]T, name string) bool {
+	_, ok := m[name]
+	return ok
+}
+
+// lookupError returns a not-found or type-mismatch error for name
+func (pc PropertyContainer) lookupError(name, want string) error {
+	got := pc.Type(name)
+	if got == "" {
+		return notFound(name)
+	}
+	return mismatch(name, want, got)
+}
+
+// GetBool returns the value of a BoolProperty
+func (pc PropertyContainer) GetBool(name string) (bool, error) {
+	if prop, ok := pc.BoolProperties[name]; ok {
+		return prop.Value, nil
+	}
+	return false, pc.lookupError(name, "BoolProperty")
+}
+
+// GetInt returns the value of an integer property. A Uint64Property too
+// large for int64 is a type mismatch rather than wrapping to a negative value.
+func (pc PropertyContainer) GetInt(name string) (int64, error) {
+	if prop, ok := pc.Int32Properties[name]; ok {
+		return int64(prop.Value), nil
+	}
+	if prop, ok := pc.Uint32Properties[name]; ok {
+		return int64(prop.Value), nil
+	}
+	if prop, ok := pc.Int64Properties[name]; ok {
+		return int64(prop.Value), nil
+	}
+	if prop, ok := pc.Int8Properties[name]; ok {
+		return int64(prop.Value), nil
+	}
+	if prop, ok := pc.Uint64Properties[name]; ok {
+		if prop.Value > math.MaxInt64 {
+			return 0, mismatch(name, "IntProperty up to MaxInt64", fmt.Sprintf("Uint64Property %d", prop.Value))
+		}
+		return int64(prop.Value), nil
+	}
+	return 0, pc.lookupError(name, "IntProperty")
+}
+
+// GetFloat returns the value of a floating point property
+func (pc PropertyContainer) GetFloat(name string) (float64, error) {
+	if prop, ok := pc.FloatProperties[name]; ok {
+		return prop.Value, nil
+	}
+	if prop, ok := pc.DoubleProperties[name]; ok {
+		return prop.Value, nil
+	}
+	return 0, pc.lookupError(name, "FloatProperty")
+}
+
+// GetString returns the value of a StrProperty or NameProperty
+func (pc PropertyContainer) GetString(name string) (string, error) {
+	if prop, ok := pc.StrProperties[name]; ok {
+		return prop.Value, nil
+	}
+	if prop, ok := pc.NameProperties[name]; ok {
+		return prop.Value, nil
+	}
+	return "", pc.lookupError(name, "StrProperty")
+}
+
+// GetText returns the value of a TextProperty
+func (pc PropertyContainer) GetText(name string) (TextValue, error) {
+	if prop, ok := pc.TextProperties[name]; ok {
+		return prop.Value, nil
+	}
+	return TextValue{}, pc.lookupError(name, "TextProperty")
+}
+
+// GetSoftObjectRef returns the reference held by a SoftObjectProperty
+func (pc PropertyContainer) GetSoftObjectRef(name string) (SoftObjectReference, error) {
+	if prop, ok := pc.SoftObjectProperties[name]; ok {
+		return prop.Value, nil
+	}
+	return SoftObjectReference{}, pc.lookupError(name, "SoftObjectProperty")
+}
+
+// GetIntArray returns the values of an integer or byte array property
+func (pc PropertyContainer) GetIntArray(name string) ([]int64, error) {
+	if prop, ok := pc.Int32ArrayProperties[name]; ok {
+		values := make([]int64, len(prop.Values))
+		for i, v := range prop.Values {
+			values[i] = int64(v)
+		}
+		return values, nil
+	}
+	if prop, ok := pc.Int64ArrayProperties[name]; ok {
+		values := make([]int64, len(prop.Values))
+		for i, v := range prop.Values {
+			values[i] = int64(v)
+		}
+		return values, nil
+	}
+	if prop, ok := pc.ByteArrayProperties[name]; ok {
+		values := make([]int64, len(prop.Values))
+		for i, v := range prop.Values {
+			values[i] = int64(v)
+		}
+		return values, nil
+	}
+	return nil, pc.lookupError(name, "IntArrayProperty")
+}
+
+// GetFloatArray returns the values of a float or double array property
+func (pc PropertyContainer) GetFloatArray(name string) ([]float64, error) {
+	if prop, ok := pc.FloatArrayProperties[name]; ok {
+		return prop.Values, nil
+	}
+	return nil, pc.lookupError(name, "FloatArrayProperty")
+}
+
+// GetStringArray returns the values of a string, name or enum array property
+func (pc PropertyContainer) GetStringArray(name string) ([]string, error) {
+	if prop, ok := pc.StrArrayProperties[name]; ok {
+		return prop.Values, nil
+	}
+	if prop, ok := pc.EnumArrayProperties[name]; ok {
+		return prop.Values, nil
+	}
+	return nil, pc.lookupError(name, "StrArrayProperty")
+}
+
+// GetEnum returns the value of an EnumProperty, e.g. "EGamePhase::EGP_EarlyGame"
+func (pc PropertyContainer) GetEnum(name string) (string, error) {
+	if prop, ok := pc.EnumProperties[name]; ok {
+		return prop.Value.Value, nil
+	}
+	return "", pc.lookupError(name, "EnumProperty")
+}
+
+// GetObjectRef returns the reference held by an ObjectProperty
+func (pc PropertyContainer) GetObjectRef(name string) (ObjectReference, error) {
+	if prop, ok := pc.ObjectProperties[name]; ok {
+		return prop.Value, nil
+	}
+	return ObjectReference{}, pc.lookupError(name, "ObjectProperty")
+}
+
+// GetObjectRefs returns the references held by an ObjectArrayProperty
+func (pc PropertyContainer) GetObjectRefs(name string) ([]ObjectReference, error) {
+	if prop, ok := pc.ObjectArrayProperties[name]; ok {
+		return prop.Values, nil
+	}
+	return nil, pc.lookupError(name, "ObjectArrayProperty")
+}
+
+// GetStruct returns a StructProperty
+func (pc PropertyContainer) GetStruct(name string) (StructProperty, error) {
+	if prop, ok := pc.StructProperties[name]; ok {
+		return prop, nil
+	}
+	return StructProperty{}, pc.lookupError(name, "StructProperty")
+}
+
+// GetStructArray returns a StructArrayProperty
+func (pc PropertyContainer) GetStructArray(name string) (StructArrayProperty, error) {
+	if prop, ok := pc.StructArrayProperties[name]; ok {
+		return prop, nil
+	}
+	return StructArrayProperty{}, pc.lookupError(name, "StructArrayProperty")
+}
+
+// GetPath resolves a dotted path with optional indexes such as
+// "mInventoryStacks[0].Item.ItemName" into struct and array values
+func (pc PropertyContainer) GetPath(path string) (interface{}, error) {
+	name, index, rest, err := splitPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var value interface{}
+	switch pc.Type(name) {
+	case "":
+		return nil, notFound(name)
+	case "StructProperty":
+		value = pc.StructProperties[name].Value
+	case "StructArrayProperty":
+		values := pc.StructArrayProperties[name].Values
+		items := make([]interface{}, len(values))
+		for i, v := range values {
+			items[i] = v
+		}
+		value = items
+	case "ObjectArrayProperty":
+		refs := pc.ObjectArrayProperties[name].Values
+		items := make([]interface{}, len(refs))
+		for i, ref := range refs {
+			items[i] = map[string]interface{}{"levelName": ref.LevelName, "pathName": ref.PathName}
+		}
+		value = items
+	case "ObjectProperty":
+		ref := pc.ObjectProperties[name].Value
+		value = map[string]interface{}{"levelName": ref.LevelName, "pathName": ref.PathName}
+	default:
+		value = pc.scalar(name)
+	}
+
+	if index >= 0 {
+		if value, err = indexValue(name, value, index); err != nil {
+			return nil, err
+		}
+	}
+	if rest == "" {
+		return value, nil
+	}
+	return LookupPath(value, rest)
+}
+
+// GetPathString resolves a path that must end in a string
+func (pc PropertyContainer) GetPathString(path string) (string, error) {
+	value, err := pc.GetPath(path)
+	if err != nil {
+		return "", err
+	}
+	s, ok := value.(string)
+	if !ok {
+		return "", mismatch(path, "string", fmt.Sprintf("%T", value))
+	}
+	return s, nil
+}
+
+// GetPathNumber resolves a path that must end in a number
+func (pc PropertyContainer) GetPathNumber(path string) (float64, error) {
+	value, err := pc.GetPath(path)
+	if err != nil {
+		return 0, err
+	}
+	return toNumber(path, value)
+}
+
+// scalar returns the plain value of a non-container property
+func (pc PropertyContainer) scalar(name string) interface{} {
+	if v, err := pc.GetBool(name); err == nil {
+		return v
+	}
+	if v, err := pc.GetInt(name); err == nil {
+		return float64(v)
+	}
+	if v, err := pc.GetFloat(name); err == nil {
+		return v
+	}
+	if v, err := pc.GetString(name); err == nil {
+		return v
+	}
+	if v, err := pc.GetEnum(name); err == nil {
+		return v
+	}
+	if prop, ok := pc.ByteProperties[name]; ok {
+		return float64(prop.Value.Value)
+	}
+	if prop, ok := pc.MapProperties[name]; ok {
+		items := make([]interface{}, len(prop.Values))
+		for i, v := range prop.Values {
+			items[i] = v
+		}
+		return items
+	}
+	if prop, ok := pc.Uint32SetProperties[name]; ok {
+		items := make([]interface{}, len(prop.Values))
+		for i, v := range prop.Values {
+			items[i] = float64(v)
+		}
+		return items
+	}
+	if prop, ok := pc.SetProperties[name]; ok {
+		return prop.Values
+	}
+	if v, err := pc.GetText(name); err == nil {
+		return v.Value
+	}
+	if v, err := pc.GetSoftObjectRef(name); err == nil {
+		return map[string]interface{}{"pathName": v.PathName, "subPathString": v.SubPathString}
+	}
+	if v, err := pc.GetIntArray(name); err == nil {
+		items := make([]interface{}, len(v))
+		for i, n := range v {
+			items[i] = float64(n)
+		}
+		return items
+	}
+	if v, err := pc.GetFloatArray(name); err == nil {
+		items := make([]interface{}, len(v))
+		for i, f := range v {
+			items[i] = f
+		}
+		return items
+	}
+	if v, err := pc.GetStringArray(name); err == nil {
+		items := make([]interface{}, len(v))
+		for i, str := range v {
+			items[i] = str
+		}
+		return items
+	}
+	if prop, ok := pc.BoolArrayProperties[name]; ok {
+		items := make([]interface{}, len(prop.Values))
+		for i, b := range prop.Values {
+			items[i] = b
+		}
+		return items
+	}
+	if prop, ok := pc.SoftObjectArrayProperties[name]; ok {
+		items := make([]interface{}, len(prop.Values))
+		for i, ref := range prop.Values {
+			items[i] = map[string]interface{}{"pathName": ref.PathName, "subPathString": ref.SubPathString}
+		}
+		return items
+	}
+	if raw, ok := pc.UnknownProperties[name]; ok {
+		var v interface{}
+		if err := json.Unmarshal(raw, &v); err == nil {
+			return v
+		}
+	}
+	return nil
+}
+
+// LookupPath resolves a dotted path into a decoded struct value. Dynamic
+// structs keep their fields under "properties" with each field wrapped in a
+// property, both of which are unwrapped transparently. Keys match
+// case-insensitively when there is no exact match.
+func LookupPath(value interface{}, path string) (interface{}, error) {
+	for path != "" {
+		name, index, rest, err := splitPath(path)
+		if err != nil {
+			return nil, err
+		}
+
+		fields, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, mismatch(name, "struct", fmt.Sprintf("%T", value))
+		}
+		if value, ok = structField(fields, name); !ok {
+			return nil, notFound(name)
+		}
+		if index >= 0 {
+			if value, err = indexValue(name, value, index); err != nil {
+				return nil, err
+			}
+		}
+		path = rest
+	}
+	return value, nil
+}
+
+// LookupString resolves a path that must end in a string
+func LookupString(value interface{}, path string) (string, error) {
+	v, err := LookupPath(value, path)
+	if err != nil {
+		return "", err
+	}
+	s, ok := v.(string)
+	if !ok {
+		return "", mismatch(path, "string", fmt.Sprintf("%T", v))
+	}
+	return s, nil
+}
+
+// LookupNumber resolves a path that must end in a number
+func LookupNumber(value interface{}, path string) (float64, error) {
+	v, err := LookupPath(value, path)
+	if err != nil {
+		return 0, err
+	}
+	return toNumber(path, v)
+}
+
+func structField(fields map[string]interface{}, name string) (interface{}, bool) {
+	if v, ok := fields[name]; ok {
+		return v, true
+	}
+	if props, ok := fields["properties"].(map[string]interface{}); ok {
+		if prop, ok := props[name]; ok {
+			if wrapped, ok := prop.(map[string]interface{}); ok {
+				if v, ok := wrapped["value"]; ok {
+					return v, true
+				}
+				if v, ok := wrapped["values"]; ok {
+					return v, true
+				}
+			}
+			return prop, true
+		}
+	}
+	for key, v := range fields {
+		if strings.EqualFold(key, name) {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+func indexValue(name string, value interface{}, index int) (interface{}, error) {
+	items, ok := value.([]interface{})
+	if !ok {
+		return nil, mismatch(name, "array", fmt.Sprintf("%T", value))
+	}
+	if index >= len(items) {
+		return nil, notFound(fmt.Sprintf("%s[%d]", name, index))
+	}
+	return items[index], nil
+}
+
+func toNumber(path string, value interface{}) (float64, error) {
+	switch v := value.(type) {
+	case float64:
+		return v, nil
+	case string:
+		// Large integers are serialized as strings
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			return f, nil
+		}
+	}
+	return 0, mismatch(path, "number", fmt.Sprintf("%T", value))
+}
+
+// splitPath splits "name[2].rest" into its name, index (-1 if absent) and remainder
+func splitPath(path string) (name string, index int, rest string, err error) {
+	name, rest, _ = strings.Cut(path, ".")
+	index = -1
+	if open := strings.IndexByte(name, '['); open >= 0 {
+		if !strings.HasSuffix(name, "]") {
+			return "", 0, "", fmt.Errorf("invalid path segment %q", name)
+		}
+		index, err = strconv.Atoi(name[open+1 : len(name)-1])
+		if err != nil || index < 0 {
+			return "", 0, "", fmt.Errorf("invalid index in path segment %q", name)
+		}
+		name = name[:open]
+	}
+	if name == "" {
+		return "", 0, "", fmt.Errorf("invalid path %q", path)
+	}
+	return name, index, rest, nil
+}
+
+// withObject attaches the object name to property errors
+func (g *GameObject) withObject(err error) error {
+	var propErr *PropertyError
+	if errors.As(err, &propErr) && propErr.Object == "" {
+		propErr.Object = g.InstanceName
+	}
+	return err
+}
+
+// GetBool returns the value of a BoolProperty on the object
+func (g *GameObject) GetBool(name string) (bool, error) {
+	v, err := g.Properties.GetBool(name)
+	return v, g.withObject(err)
+}
+
+// GetInt returns the value of an integer property on the object
+func (g *GameObject) GetInt(name string) (int64, error) {
+	v, err := g.Properties.GetInt(name)
+	return v, g.withObject(err)
+}
+
+// GetFloat returns the value of a floating point property on the object
+func (g *GameObject) GetFloat(name string) (float64, error) {
+	v, err := g.Properties.GetFloat(name)
+	return v, g.withObject(err)
+}
+
+// GetString returns the value of a StrProperty on the object
+func (g *GameObject) GetString(name string) (string, error) {
+	v, err := g.Properties.GetString(name)
+	return v, g.withObject(err)
+}
+
+// GetEnum returns the value of an EnumProperty on the object
+func (g *GameObject) GetEnum(name string) (string, error) {
+	v, err := g.Properties.GetEnum(name)
+	return v, g.withObject(err)
+}
+
+// GetText returns the value of a TextProperty on the object
+func (g *GameObject) GetText(name string) (TextValue, error) {
+	v, err := g.Properties.GetText(name)
+	return v, g.withObject(err)
+}
+
+// GetSoftObjectRef returns the reference held by a SoftObjectProperty on the object
+func (g *GameObject) GetSoftObjectRef(name string) (SoftObjectReference, error) {
+	v, err := g.Properties.GetSoftObjectRef(name)
+	return v, g.withObject(err)
+}
+
+// GetIntArray returns the values of an integer array property on the object
+func (g *GameObject) GetIntArray(name string) ([]int64, error) {
+	v, err := g.Properties.GetIntArray(name)
+	return v, g.withObject(err)
+}
+
+// GetFloatArray returns the values of a float array property on the object
+func (g *GameObject) GetFloatArray(name string) ([]float64, error) {
+	v, err := g.Properties.GetFloatArray(name)
+	return v, g.withObject(err)
+}
+
+// GetStringArray returns the values of a string array property on the object
+func (g *GameObject) GetStringArray(name string) ([]string, error) {
+	v, err := g.Properties.GetStringArray(name)
+	return v, g.withObject(err)
+}
+
+// GetObjectRef returns the reference held by an ObjectProperty on the object
+func (g *GameObject) GetObjectRef(name string) (ObjectReference, error) {
+	v, err := g.Properties.GetObjectRef(name)
+	return v, g.withObject(err)
+}
+
+// GetObjectRefs returns the references held by an ObjectArrayProperty on the object
+func (g *GameObject) GetObjectRefs(name string) ([]ObjectReference, error) {
+	v, err := g.Properties.GetObjectRefs(name)
+	return v, g.withObject(err)
+}
+
+// GetStruct returns a StructProperty on the object
+func (g *GameObject) GetStruct(name string) (StructProperty, error) {
+	v, err := g.Properties.GetStruct(name)
+	return v, g.withObject(err)
+}
+
+// GetStructArray returns a StructArrayProperty on the object
+func (g *GameObject) GetStructArray(name string) (StructArrayProperty, error) {
+	v, err := g.Properties.GetStructArray(name)
+	return v, g.withObject(err)
+}
+
+// GetPath resolves a property path on the object
+func (g *GameObject) GetPath(path string) (interface{}, error) {
+	v, err := g.Properties.GetPath(path)
+	return v, g.withObject(err)
+}
+
+// GetFloatOr returns a float property, or fallback when the save omits it
+// because it still holds its default value. Type mismatches are still errors.
+func (g *GameObject) GetFloatOr(name string, fallback float64) (float64, error) {
+	v, err := g.GetFloat(name)
+	if errors.Is(err, ErrPropertyNotFound) {
+		return fallback, nil
+	}
+	return v, err
+}
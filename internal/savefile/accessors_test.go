@@ -0,0 +1,140 @@
+package savefile
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestSplitPath(t *testing.T) {
+	tests := []struct {
+		path      string
+		wantName  string
+		wantIndex int
+		wantRest  string
+		wantErr   bool
+	}{
+		{path: "mHealth", wantName: "mHealth", wantIndex: -1},
+		{path: "mStacks[2]", wantName: "mStacks", wantIndex: 2},
+		{path: "mStacks[0].Item.ItemName", wantName: "mStacks", wantIndex: 0, wantRest: "Item.ItemName"},
+		{path: "Item.ItemName", wantName: "Item", wantIndex: -1, wantRest: "ItemName"},
+		{path: "mStacks[", wantErr: true},
+		{path: "mStacks[x]", wantErr: true},
+		{path: "mStacks[-1]", wantErr: true},
+		{path: "[1]", wantErr: true},
+		{path: "", wantErr: true},
+		{path: ".Item", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			name, index, rest, err := splitPath(tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("splitPath(%q) error = %v, wantErr %v", tt.path, err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if name != tt.wantName || index != tt.wantIndex || rest != tt.wantRest {
+				t.Errorf("splitPath(%q) = %q, %d, %q, want %q, %d, %q", tt.path, name, index, rest, tt.wantName, tt.wantIndex, tt.wantRest)
+			}
+		})
+	}
+}
+
+func TestLookupPath(t *testing.T) {
+	var value interface{}
+	err := json.Unmarshal([]byte(`{
+		"itemReference": {"levelName": "", "pathName": "/Game/Desc_Screw.Desc_Screw_C"},
+		"Counts": [1, 2, 3],
+		"properties": {
+			"NumItems": {"type": "Int32Property", "value": 42},
+			"Tags": {"type": "ArrayProperty", "values": ["a", "b"]},
+			"Raw": "plain"
+		}
+	}`), &value)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path    string
+		want    interface{}
+		wantErr error
+	}{
+		{path: "itemReference.pathName", want: "/Game/Desc_Screw.Desc_Screw_C"},
+		{path: "ItemReference.PathName", want: "/Game/Desc_Screw.Desc_Screw_C"},
+		{path: "Counts[1]", want: 2.0},
+		{path: "NumItems", want: 42.0},
+		{path: "Tags[1]", want: "b"},
+		{path: "Raw", want: "plain"},
+		{path: "", want: value},
+		{path: "missing", wantErr: ErrPropertyNotFound},
+		{path: "Counts[3]", wantErr: ErrPropertyNotFound},
+		{path: "Counts.x", wantErr: ErrTypeMismatch},
+		{path: "itemReference[0]", wantErr: ErrTypeMismatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, err := LookupPath(value, tt.path)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("LookupPath(%q) error = %v, want %v", tt.path, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LookupPath(%q) error = %v", tt.path, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("LookupPath(%q) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetInt(t *testing.T) {
+	var pc PropertyContainer
+	err := json.Unmarshal([]byte(`{
+		"Small": {"type": "Int8Property", "value": -3},
+		"Int": {"type": "Int32Property", "value": 42},
+		"Unsigned": {"type": "Uint32Property", "value": 4294967295},
+		"Long": {"type": "Int64Property", "value": "-9223372036854775808"},
+		"MaxUnsigned": {"type": "UInt64Property", "value": "9223372036854775807"},
+		"Overflow": {"type": "UInt64Property", "value": "9223372036854775808"},
+		"Float": {"type": "FloatProperty", "value": 1.5}
+	}`), &pc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		want    int64
+		wantErr error
+	}{
+		{name: "Small", want: -3},
+		{name: "Int", want: 42},
+		{name: "Unsigned", want: 4294967295},
+		{name: "Long", want: math.MinInt64},
+		{name: "MaxUnsigned", want: math.MaxInt64},
+		{name: "Overflow", wantErr: ErrTypeMismatch},
+		{name: "Float", wantErr: ErrTypeMismatch},
+		{name: "Missing", wantErr: ErrPropertyNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := pc.GetInt(tt.name)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("GetInt(%q) = %d, %v, want error %v", tt.name, got, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("GetInt(%q) = %d, %v, want %d", tt.name, got, err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package savefile
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// ParseDiagnostic records a property that could not be decoded into a typed value
+type ParseDiagnostic struct {
+	Object string `json:"object"`
+	// Name is the property, or a path such as "mStacks[2].Item" for
+	// properties nested in structs
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Error string `json:"error"`
+	// Dropped is false when the property was kept as raw JSON, in
+	// UnknownProperties or in the value of the struct holding it
+	Dropped bool `json:"dropped"`
+}
+
+func (d ParseDiagnostic) String() string {
+	return fmt.Sprintf("%s: %s (%s): %s", d.Object, d.Name, d.Type, d.Error)
+}
+
+// SetStrict makes unmarshaling fail when any property was dropped or had
+// an unknown type. It must be called before the save file is decoded.
+func (sf *SaveFile) SetStrict(strict bool) {
+	sf.strict = strict
+}
+
+// Diagnostics returns every property that was dropped or kept raw while decoding
+func (sf SaveFile) Diagnostics() []ParseDiagnostic {
+	return sf.diagnostics
+}
+
+// collectDiagnostics gathers the per-object property diagnostics and fails
+// in strict mode if there are any
+func (sf *SaveFile) collectDiagnostics() error {
+	sf.diagnostics = append([]ParseDiagnostic(nil), sf.lightweightDiagnostics...)
+	for _, obj := range sf.cachedObjects {
+		for _, diag := range obj.Properties.diagnostics {
+			diag.Object = obj.InstanceName
+			sf.diagnostics = append(sf.diagnostics, diag)
+		}
+	}
+
+	if sf.strict && len(sf.diagnostics) > 0 {
+		return fmt.Errorf("strict parse failed: %d properties dropped or of unknown type, first: %s", len(sf.diagnostics), sf.diagnostics[0])
+	}
+	return nil
+}
+
+// knownPropertyTypes lists every property type PropertyContainer decodes
+// into a typed map, including the element types of ArrayProperty
+var knownPropertyTypes = map[string]bool{
+	"BoolProperty": true, "Int8Property": true, "Int32Property": true, "IntProperty": true,
+	"Int64Property": true, "Uint32Property": true, "UInt32Property": true, "UInt64Property": true,
+	"Uint64Property": true, "FloatProperty": true, "DoubleProperty": true, "StrProperty": true,
+	"NameProperty": true, "TextProperty": true, "ObjectProperty": true, "InterfaceProperty": true,
+	"SoftObjectProperty": true, "EnumProperty": true, "ByteProperty": true, "StructProperty": true,
+	"ArrayProperty": true, "MapProperty": true, "SetProperty": true,
+	"ObjectArrayProperty": true, "SoftObjectArrayProperty": true, "StructArrayProperty": true,
+	"Int32ArrayProperty": true, "Int64ArrayProperty": true, "FloatArrayProperty": true,
+	"DoubleArrayProperty": true, "StrArrayProperty": true, "EnumArrayProperty": true,
+	"ByteArrayProperty": true, "BoolArrayProperty": true, "Uint32SetProperty": true,
+	"Int32SetProperty": true, "ObjectSetProperty": true, "StrSetProperty": true, "StructSetProperty": true,
+}
+
+// nestedDiagnostics reports properties of unknown type inside the decoded
+// value of a struct, array or map property named path. Dynamic structs keep
+// their fields under "properties", each wrapped in a property with a type.
+func nestedDiagnostics(path string, value interface{}) []ParseDiagnostic {
+	var diags []ParseDiagnostic
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			props, ok := field.(map[string]interface{})
+			if key != "properties" || !ok {
+				diags = append(diags, nestedDiagnostics(path+"."+key, field)...)
+				continue
+			}
+			for name, raw := range props {
+				prop, ok := raw.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				if typ, _ := prop["type"].(string); typ != "" && !knownPropertyTypes[typ] {
+					diags = append(diags, ParseDiagnostic{Name: path + "." + name, Type: typ, Error: "unknown property type"})
+				}
+				for _, field := range []string{"value", "values"} {
+					if nested, ok := prop[field]; ok {
+						diags = append(diags, nestedDiagnostics(path+"."+name, nested)...)
+					}
+				}
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			diags = append(diags, nestedDiagnostics(path+"["+strconv.Itoa(i)+"]", item)...)
+		}
+	}
+	return diags
+}
@@ -33,10 +33,13 @@ func (g *GameObject) cacheData() {
 		g.instance = parts[len(parts)-1]
 	}
 
-	parts = strings.Split(g.TypePath, ".")
-	if len(parts) >= 1 {
-		g.simpleType = parts[len(parts)-1]
-	}
+	g.simpleType = simpleName(g.TypePath)
+}
+
+// simpleName returns the class portion of a type path
+func simpleName(typePath string) string {
+	parts := strings.Split(typePath, ".")
+	return parts[len(parts)-1]
 }
 
 func (g *GameObject) Instance() string {
@@ -0,0 +1,135 @@
+package savefile
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// LightweightBuildable is a single buildable instance stored in the
+// FGLightweightBuildableSubsystem instead of as its own GameObject
+type LightweightBuildable struct {
+	TypePath       string               `json:"typePath"`
+	Index          int                  `json:"index"`
+	Transform      Transform            `json:"transform"`
+	Recipe         string               `json:"recipe"`
+	BlueprintProxy string               `json:"blueprintProxy,omitempty"`
+	Customization  FactoryCustomization `json:"customization"`
+
+	simpleType string
+}
+
+// FactoryCustomization holds the paint and material applied to a buildable
+type FactoryCustomization struct {
+	Swatch          string      `json:"swatch,omitempty"`
+	Material        string      `json:"material,omitempty"`
+	Pattern         string      `json:"pattern,omitempty"`
+	PatternRotation int         `json:"patternRotation,omitempty"`
+	Skin            string      `json:"skin,omitempty"`
+	PaintFinish     string      `json:"paintFinish,omitempty"`
+	PrimaryColor    LinearColor `json:"primaryColor"`
+	SecondaryColor  LinearColor `json:"secondaryColor"`
+}
+
+// SimpleType returns the class portion of the buildable's type path
+func (lb LightweightBuildable) SimpleType() string {
+	if lb.simpleType == "" {
+		return simpleName(lb.TypePath)
+	}
+	return lb.simpleType
+}
+
+// lightweightInstance mirrors a single entry in the subsystem's instance arrays
+type lightweightInstance struct {
+	Transform       Transform       `json:"transform"`
+	PrimaryColor    LinearColor     `json:"primaryColor"`
+	SecondaryColor  LinearColor     `json:"secondaryColor"`
+	UsedSwatchSlot  ObjectReference `json:"usedSwatchSlot"`
+	UsedMaterial    ObjectReference `json:"usedMaterial"`
+	UsedPattern     ObjectReference `json:"usedPattern"`
+	UsedSkin        ObjectReference `json:"usedSkin"`
+	UsedPaintFinish ObjectReference `json:"usedPaintFinish"`
+	PatternRotation int             `json:"patternRotation"`
+	UsedRecipe      ObjectReference `json:"usedRecipe"`
+	BlueprintProxy  ObjectReference `json:"blueprintProxy"`
+}
+
+// lightweightSubsystemProperties mirrors the special properties of the
+// lightweight buildable subsystem. Buildables and their instances are kept
+// raw so one that fails to decode does not lose the others.
+type lightweightSubsystemProperties struct {
+	Buildables []json.RawMessage `json:"buildables"`
+}
+
+type lightweightBuildableType struct {
+	TypeReference ObjectReference   `json:"typeReference"`
+	Instances     []json.RawMessage `json:"instances"`
+}
+
+// LightweightBuildables returns every instance held by the lightweight buildable subsystem
+func (sf SaveFile) LightweightBuildables() []LightweightBuildable {
+	return sf.lightweightBuildables
+}
+
+// decodeLightweightBuildables returns the instances of the subsystem obj,
+// with a diagnostic for every buildable type or instance that failed to decode
+func decodeLightweightBuildables(obj *GameObject) ([]LightweightBuildable, []ParseDiagnostic, error) {
+	if obj.SpecialProperties == nil {
+		return nil, nil, nil
+	}
+
+	raw, err := json.Marshal(obj.SpecialProperties)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to re-encode lightweight buildables: %w", err)
+	}
+	var props lightweightSubsystemProperties
+	if err := json.Unmarshal(raw, &props); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode lightweight buildables: %w", err)
+	}
+
+	var result []LightweightBuildable
+	var diags []ParseDiagnostic
+	for b, rawBuildable := range props.Buildables {
+		var buildable lightweightBuildableType
+		if err := json.Unmarshal(rawBuildable, &buildable); err != nil {
+			diags = append(diags, ParseDiagnostic{
+				Object:  obj.InstanceName,
+				Name:    fmt.Sprintf("buildables[%d]", b),
+				Error:   err.Error(),
+				Dropped: true,
+			})
+			continue
+		}
+		for i, rawInstance := range buildable.Instances {
+			var instance lightweightInstance
+			if err := json.Unmarshal(rawInstance, &instance); err != nil {
+				diags = append(diags, ParseDiagnostic{
+					Object:  obj.InstanceName,
+					Name:    fmt.Sprintf("buildables[%d].instances[%d]", b, i),
+					Type:    buildable.TypeReference.PathName,
+					Error:   err.Error(),
+					Dropped: true,
+				})
+				continue
+			}
+			result = append(result, LightweightBuildable{
+				TypePath:       buildable.TypeReference.PathName,
+				Index:          i,
+				simpleType:     simpleName(buildable.TypeReference.PathName),
+				Transform:      instance.Transform,
+				Recipe:         instance.UsedRecipe.PathName,
+				BlueprintProxy: instance.BlueprintProxy.PathName,
+				Customization: FactoryCustomization{
+					Swatch:          instance.UsedSwatchSlot.PathName,
+					Material:        instance.UsedMaterial.PathName,
+					Pattern:         instance.UsedPattern.PathName,
+					PatternRotation: instance.PatternRotation,
+					Skin:            instance.UsedSkin.PathName,
+					PaintFinish:     instance.UsedPaintFinish.PathName,
+					PrimaryColor:    instance.PrimaryColor,
+					SecondaryColor:  instance.SecondaryColor,
+				},
+			})
+		}
+	}
+	return result, diags, nil
+}
@@ -0,0 +1,161 @@
+package savefile
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ErrDanglingReference is returned when a reference points at an object missing from the save
+var ErrDanglingReference = errors.New("dangling reference")
+
+// Reference is a link from one object to another
+type Reference struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	// Property names where the reference was found, e.g. "mInventory",
+	// "mInventoryStacks[0].Item", "components" or "parentEntityName"
+	Property string `json:"property"`
+}
+
+// References returns every reference held by the named object
+func (sf SaveFile) References(name string) []Reference {
+	return sf.forwardRefs[name]
+}
+
+// ReferencedBy returns every reference pointing at the named object
+func (sf SaveFile) ReferencedBy(name string) []Reference {
+	return sf.reverseRefs[name]
+}
+
+// Resolve returns the object a reference points at. Asset references such as
+// recipes and item descriptors are not part of the save and resolve to nil
+// without an error; missing level objects return ErrDanglingReference.
+func (sf SaveFile) Resolve(ref ObjectReference) (*GameObject, error) {
+	if ref.PathName == "" {
+		return nil, nil
+	}
+	if obj, ok := sf.cachedObjects[ref.PathName]; ok {
+		return obj, nil
+	}
+	if !isLevelReference(ref) {
+		return nil, nil
+	}
+	return nil, fmt.Errorf("%s: %w", ref.PathName, ErrDanglingReference)
+}
+
+// DanglingReferences returns every reference to a level object missing from the save
+func (sf SaveFile) DanglingReferences() []Reference {
+	return sf.danglingRefs
+}
+
+// isLevelReference reports whether a reference targets an object placed in a
+// level rather than a game asset
+func isLevelReference(ref ObjectReference) bool {
+	return ref.LevelName != "" || strings.Contains(ref.PathName, "PersistentLevel.")
+}
+
+// buildReferenceIndex walks every object and records its forward and reverse references
+func (sf *SaveFile) buildReferenceIndex() {
+	sf.forwardRefs = make(map[string][]Reference)
+	sf.reverseRefs = make(map[string][]Reference)
+	sf.danglingRefs = nil
+
+	for name, obj := range sf.cachedObjects {
+		add := func(property string, ref ObjectReference) {
+			if ref.PathName == "" {
+				return
+			}
+			reference := Reference{From: name, To: ref.PathName, Property: property}
+			sf.forwardRefs[name] = append(sf.forwardRefs[name], reference)
+			sf.reverseRefs[ref.PathName] = append(sf.reverseRefs[ref.PathName], reference)
+			if _, ok := sf.cachedObjects[ref.PathName]; !ok && isLevelReference(ref) {
+				sf.danglingRefs = append(sf.danglingRefs, reference)
+			}
+		}
+
+		if obj.ParentEntityName != "" {
+			add("parentEntityName", ObjectReference{PathName: obj.ParentEntityName, LevelName: obj.ParentObject.LevelName})
+		}
+		for _, component := range obj.Components {
+			add("components", ObjectReference(component))
+		}
+
+		props := obj.Properties
+		for prop, value := range props.ObjectProperties {
+			add(prop, value.Value)
+		}
+		for prop, value := range props.ObjectArrayProperties {
+			for i, ref := range value.Values {
+				add(fmt.Sprintf("%s[%d]", prop, i), ref)
+			}
+		}
+		for prop, value := range props.StructProperties {
+			walkReferences(prop, value.Value, add)
+		}
+		for prop, value := range props.StructArrayProperties {
+			for i, v := range value.Values {
+				walkReferences(fmt.Sprintf("%s[%d]", prop, i), v, add)
+			}
+		}
+		for prop, value := range props.MapProperties {
+			for i, v := range value.Values {
+				walkReferences(fmt.Sprintf("%s[%d]", prop, i), v, add)
+			}
+		}
+		for prop, value := range props.SetProperties {
+			for i, v := range value.Values {
+				walkReferences(fmt.Sprintf("%s[%d]", prop, i), v, add)
+			}
+		}
+	}
+
+	// Objects, properties and struct fields are all walked in map order, so
+	// sort for stable output from the reference APIs
+	for _, refs := range sf.forwardRefs {
+		sortReferences(refs)
+	}
+	for _, refs := range sf.reverseRefs {
+		sortReferences(refs)
+	}
+	sortReferences(sf.danglingRefs)
+}
+
+// sortReferences orders references by source object, property and target
+func sortReferences(refs []Reference) {
+	sort.Slice(refs, func(i, j int) bool {
+		if refs[i].From != refs[j].From {
+			return refs[i].From < refs[j].From
+		}
+		if refs[i].Property != refs[j].Property {
+			return refs[i].Property < refs[j].Property
+		}
+		return refs[i].To < refs[j].To
+	})
+}
+
+// walkReferences finds object references nested anywhere in a decoded struct value
+func walkReferences(path string, value interface{}, add func(string, ObjectReference)) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if pathName, ok := v["pathName"].(string); ok {
+			levelName, _ := v["levelName"].(string)
+			add(path, ObjectReference{LevelName: levelName, PathName: pathName})
+			return
+		}
+		for key, inner := range v {
+			// Dynamic structs wrap their fields in "properties" and each field in "value"
+			switch key {
+			case "properties", "value", "values":
+				walkReferences(path, inner, add)
+			default:
+				walkReferences(path+"."+key, inner, add)
+			}
+		}
+	case []interface{}:
+		for i, inner := range v {
+			walkReferences(fmt.Sprintf("%s[%d]", path, i), inner, add)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package savefile
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestReferenceOrder(t *testing.T) {
+	data := []byte(`{"header":{},"levels":{"L":{"objects":[
+		{"typePath":"/Game/Build_A.Build_A_C","instanceName":"Persistent_Level:PersistentLevel.A","properties":{
+			"mOutput":{"type":"ObjectProperty","value":{"levelName":"L","pathName":"Persistent_Level:PersistentLevel.B"}},
+			"mInput":{"type":"ObjectProperty","value":{"levelName":"L","pathName":"Persistent_Level:PersistentLevel.C"}},
+			"mTargets":{"type":"ObjectArrayProperty","values":[
+				{"levelName":"L","pathName":"Persistent_Level:PersistentLevel.C"},
+				{"levelName":"L","pathName":"Persistent_Level:PersistentLevel.Gone"}
+			]},
+			"mLink":{"type":"StructProperty","subtype":"Link","value":{"zeta":{"pathName":"Persistent_Level:PersistentLevel.B"},"alpha":{"pathName":"Persistent_Level:PersistentLevel.C"}}}
+		}},
+		{"typePath":"/Game/Build_A.Build_A_C","instanceName":"Persistent_Level:PersistentLevel.B","properties":{
+			"mPartner":{"type":"ObjectProperty","value":{"levelName":"L","pathName":"Persistent_Level:PersistentLevel.C"}}
+		}},
+		{"typePath":"/Game/Build_A.Build_A_C","instanceName":"Persistent_Level:PersistentLevel.C","properties":{}}
+	]}}}`)
+
+	const a, b, c = "Persistent_Level:PersistentLevel.A", "Persistent_Level:PersistentLevel.B", "Persistent_Level:PersistentLevel.C"
+	tests := []struct {
+		name string
+		refs func(*SaveFile) []Reference
+		want []Reference
+	}{
+		{
+			name: "forward",
+			refs: func(sf *SaveFile) []Reference { return sf.References(a) },
+			want: []Reference{
+				{From: a, To: c, Property: "mInput"},
+				{From: a, To: c, Property: "mLink.alpha"},
+				{From: a, To: b, Property: "mLink.zeta"},
+				{From: a, To: b, Property: "mOutput"},
+				{From: a, To: c, Property: "mTargets[0]"},
+				{From: a, To: "Persistent_Level:PersistentLevel.Gone", Property: "mTargets[1]"},
+			},
+		},
+		{
+			name: "reverse",
+			refs: func(sf *SaveFile) []Reference { return sf.ReferencedBy(c) },
+			want: []Reference{
+				{From: a, To: c, Property: "mInput"},
+				{From: a, To: c, Property: "mLink.alpha"},
+				{From: a, To: c, Property: "mTargets[0]"},
+				{From: b, To: c, Property: "mPartner"},
+			},
+		},
+		{
+			name: "dangling",
+			refs: func(sf *SaveFile) []Reference { return sf.DanglingReferences() },
+			want: []Reference{{From: a, To: "Persistent_Level:PersistentLevel.Gone", Property: "mTargets[1]"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Map iteration order differs between loads, so load several times
+			for i := 0; i < 20; i++ {
+				var sf SaveFile
+				if err := json.Unmarshal(data, &sf); err != nil {
+					t.Fatalf("failed to decode save: %v", err)
+				}
+				if got := tt.refs(&sf); !reflect.DeepEqual(got, tt.want) {
+					t.Fatalf("load %d: references = %+v, want %+v", i, got, tt.want)
+				}
+			}
+		})
+	}
+}
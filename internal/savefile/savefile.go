@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -17,8 +18,27 @@ type SaveFile struct {
 	CompressionInfo         CompressionInfo           `json:"compressionInfo"`
 	UnresolvedWorldSaveData []UnresolvedWorldSaveData `json:"unresolvedWorldSaveData"`
 
-	cachedObjects map[string]*GameObject
-	circuitCache  map[string]*GameObject
+	cachedObjects   map[string]*GameObject
+	objectLevels    map[string]string
+	circuitCache    map[string]*GameObject
+	destroyedActors map[string]bool
+
+	lightweightBuildables []LightweightBuildable
+	// lightweightDiagnostics records lightweight buildables that failed to decode
+	lightweightDiagnostics []ParseDiagnostic
+
+	typeIndex       map[string][]*GameObject
+	simpleTypeIndex map[string][]*GameObject
+	typePaths       []string
+	spatial         *spatialIndex
+
+	forwardRefs  map[string][]Reference
+	reverseRefs  map[string][]Reference
+	danglingRefs []Reference
+
+	strict      bool
+	diagnostics []ParseDiagnostic
+	loadStats   LoadStats
 }
 
 func (sf SaveFile) GetGameObject(key string) *GameObject {
@@ -35,10 +55,29 @@ func (sf SaveFile) GetCircuit(key string) int {
 	return -1
 }
 
+// LevelOf returns the name of the level holding the named object
+func (sf SaveFile) LevelOf(name string) string {
+	return sf.objectLevels[name]
+}
+
 func (sf SaveFile) AllGameObjects() map[string]*GameObject {
 	return sf.cachedObjects
 }
 
+// IsDestroyed reports whether an actor placed in the map was collected or destroyed
+func (sf SaveFile) IsDestroyed(pathName string) bool {
+	return sf.destroyedActors[LevelPath(pathName)]
+}
+
+// LevelPath strips the level prefix from a path name, turning
+// "Persistent_Level:PersistentLevel.BP_Crystal1" into "PersistentLevel.BP_Crystal1"
+func LevelPath(pathName string) string {
+	if idx := strings.LastIndex(pathName, ":"); idx >= 0 {
+		return pathName[idx+1:]
+	}
+	return pathName
+}
+
 // GridHash represents the grid hash information
 type GridHash struct {
 	Version int   `json:"version"`
@@ -141,6 +180,14 @@ type Vector4D struct {
 	W float64 `json:"w"`
 }
 
+// LinearColor represents an RGBA color with float components
+type LinearColor struct {
+	R float64 `json:"r"`
+	G float64 `json:"g"`
+	B float64 `json:"b"`
+	A float64 `json:"a"`
+}
+
 // Entity represents entity data
 type Entity struct {
 	LevelName     string            `json:"levelName"`
@@ -239,11 +286,22 @@ func (sf *SaveFile) UnmarshalJSON(jsonData []byte) error {
 		return err
 	}
 	sf.cachedObjects = make(map[string]*GameObject)
+	sf.objectLevels = make(map[string]string)
 	sf.circuitCache = make(map[string]*GameObject)
-	for _, level := range sf.Levels {
+	sf.destroyedActors = make(map[string]bool)
+	sf.lightweightBuildables = nil
+	sf.lightweightDiagnostics = nil
+	for _, actor := range sf.UnresolvedWorldSaveData {
+		sf.destroyedActors[LevelPath(actor.PathName)] = true
+	}
+	for levelName, level := range sf.Levels {
+		for _, collected := range level.Collectables {
+			sf.destroyedActors[LevelPath(collected.PathName)] = true
+		}
 		for _, gameObject := range level.Objects {
 			gameObject.cacheData()
 			sf.cachedObjects[gameObject.InstanceName] = &gameObject
+			sf.objectLevels[gameObject.InstanceName] = levelName
 
 			if gameObject.TypePath == "/Script/FactoryGame.FGPowerCircuit" {
 				components, ok := gameObject.Properties.ObjectArrayProperties["mComponents"]
@@ -255,10 +313,33 @@ func (sf *SaveFile) UnmarshalJSON(jsonData []byte) error {
 					sf.circuitCache[component.PathName] = &gameObject
 				}
 			}
+
+			if gameObject.TypePath == "/Script/FactoryGame.FGLightweightBuildableSubsystem" {
+				buildables, diags, err := decodeLightweightBuildables(&gameObject)
+				if err != nil {
+					// Keep the rest of the save usable without its lightweight buildables
+					diags = append(diags, ParseDiagnostic{
+						Object:  gameObject.InstanceName,
+						Name:    "buildables",
+						Type:    gameObject.TypePath,
+						Error:   err.Error(),
+						Dropped: true,
+					})
+				}
+				sf.lightweightBuildables = append(sf.lightweightBuildables, buildables...)
+				sf.lightweightDiagnostics = append(sf.lightweightDiagnostics, diags...)
+			}
 		}
 	}
 
-	return nil
+	start := time.Now()
+	sf.buildTypeIndex()
+	sf.buildSpatialIndex()
+	sf.buildReferenceIndex()
+	err = sf.collectDiagnostics()
+	sf.loadStats.Index = time.Since(start)
+
+	return err
 }
 
 // ToJSON converts the SaveFile structure to JSON
@@ -379,21 +460,182 @@ type Uint32SetProperty struct {
 	Values  []uint32 `json:"values"`
 }
 
+// Int8Property represents a signed 8-bit integer property
+type Int8Property struct {
+	Property
+	Value int8 `json:"value"`
+}
+
+// Int64Property represents a 64-bit integer property
+type Int64Property struct {
+	Property
+	Value Int64Value `json:"value"`
+}
+
+// Uint64Property represents an unsigned 64-bit integer property
+type Uint64Property struct {
+	Property
+	Value Uint64Value `json:"value"`
+}
+
+// DoubleProperty represents a double precision floating point property
+type DoubleProperty struct {
+	Property
+	Value float64 `json:"value"`
+}
+
+// NameProperty represents an FName property
+type NameProperty struct {
+	Property
+	Value string `json:"value"`
+}
+
+// TextValue represents localized FText data
+type TextValue struct {
+	Flags       int    `json:"flags"`
+	HistoryType int    `json:"historyType"`
+	Namespace   string `json:"namespace,omitempty"`
+	Key         string `json:"key,omitempty"`
+	Value       string `json:"value,omitempty"`
+}
+
+// TextProperty represents a localized text property
+type TextProperty struct {
+	Property
+	Value TextValue `json:"value"`
+}
+
+// SoftObjectReference represents a soft reference to an asset or object
+type SoftObjectReference struct {
+	InstanceName  string `json:"instanceName,omitempty"`
+	PathName      string `json:"pathName"`
+	SubPathString string `json:"subPathString,omitempty"`
+}
+
+// SoftObjectProperty represents a soft object reference property
+type SoftObjectProperty struct {
+	Property
+	Value SoftObjectReference `json:"value"`
+}
+
+// Int32ArrayProperty represents an array of 32-bit integers
+type Int32ArrayProperty struct {
+	Property
+	Subtype string  `json:"subtype"`
+	Values  []int32 `json:"values"`
+}
+
+// Int64ArrayProperty represents an array of 64-bit integers
+type Int64ArrayProperty struct {
+	Property
+	Subtype string       `json:"subtype"`
+	Values  []Int64Value `json:"values"`
+}
+
+// FloatArrayProperty represents an array of floats or doubles
+type FloatArrayProperty struct {
+	Property
+	Subtype string    `json:"subtype"`
+	Values  []float64 `json:"values"`
+}
+
+// StrArrayProperty represents an array of strings or names
+type StrArrayProperty struct {
+	Property
+	Subtype string   `json:"subtype"`
+	Values  []string `json:"values"`
+}
+
+// EnumArrayProperty represents an array of enum values
+type EnumArrayProperty struct {
+	Property
+	Subtype string   `json:"subtype"`
+	Values  []string `json:"values"`
+}
+
+// ByteArrayProperty represents an array of bytes
+type ByteArrayProperty struct {
+	Property
+	Subtype string `json:"subtype"`
+	Values  []int  `json:"values"`
+}
+
+// BoolArrayProperty represents an array of booleans
+type BoolArrayProperty struct {
+	Property
+	Subtype string `json:"subtype"`
+	Values  []bool `json:"values"`
+}
+
+// SoftObjectArrayProperty represents an array of soft object references
+type SoftObjectArrayProperty struct {
+	Property
+	Subtype string                `json:"subtype"`
+	Values  []SoftObjectReference `json:"values"`
+}
+
+// SetProperty represents a set of any element type other than uint32
+type SetProperty struct {
+	Property
+	Subtype string        `json:"subtype"`
+	Values  []interface{} `json:"values"`
+}
+
 // PropertyContainer holds all possible property types organized by type
 type PropertyContainer struct {
-	BoolProperties        map[string]BoolProperty          `json:"boolProperties,omitempty"`
-	Int32Properties       map[string]Int32Property         `json:"int32Properties,omitempty"`
-	Uint32Properties      map[string]Uint32Property        `json:"uint32Properties,omitempty"`
-	FloatProperties       map[string]FloatProperty         `json:"floatProperties,omitempty"`
-	StrProperties         map[string]StrProperty           `json:"strProperties,omitempty"`
-	ObjectProperties      map[string]ObjectProperty        `json:"objectProperties,omitempty"`
-	ObjectArrayProperties map[string]ObjectArrayProperties `json:"objectArrayProperties,omitempty"`
-	EnumProperties        map[string]EnumProperty          `json:"enumProperties,omitempty"`
-	ByteProperties        map[string]ByteProperty          `json:"byteProperties,omitempty"`
-	StructProperties      map[string]StructProperty        `json:"structProperties,omitempty"`
-	StructArrayProperties map[string]StructArrayProperty   `json:"structArrayProperties,omitempty"`
-	MapProperties         map[string]MapProperty           `json:"mapProperties,omitempty"`
-	Uint32SetProperties   map[string]Uint32SetProperty     `json:"uint32SetProperties,omitempty"`
+	BoolProperties            map[string]BoolProperty            `json:"boolProperties,omitempty"`
+	Int8Properties            map[string]Int8Property            `json:"int8Properties,omitempty"`
+	Int32Properties           map[string]Int32Property           `json:"int32Properties,omitempty"`
+	Int64Properties           map[string]Int64Property           `json:"int64Properties,omitempty"`
+	Uint32Properties          map[string]Uint32Property          `json:"uint32Properties,omitempty"`
+	Uint64Properties          map[string]Uint64Property          `json:"uint64Properties,omitempty"`
+	FloatProperties           map[string]FloatProperty           `json:"floatProperties,omitempty"`
+	DoubleProperties          map[string]DoubleProperty          `json:"doubleProperties,omitempty"`
+	StrProperties             map[string]StrProperty             `json:"strProperties,omitempty"`
+	NameProperties            map[string]NameProperty            `json:"nameProperties,omitempty"`
+	TextProperties            map[string]TextProperty            `json:"textProperties,omitempty"`
+	ObjectProperties          map[string]ObjectProperty          `json:"objectProperties,omitempty"`
+	ObjectArrayProperties     map[string]ObjectArrayProperties   `json:"objectArrayProperties,omitempty"`
+	SoftObjectProperties      map[string]SoftObjectProperty      `json:"softObjectProperties,omitempty"`
+	SoftObjectArrayProperties map[string]SoftObjectArrayProperty `json:"softObjectArrayProperties,omitempty"`
+	EnumProperties            map[string]EnumProperty            `json:"enumProperties,omitempty"`
+	ByteProperties            map[string]ByteProperty            `json:"byteProperties,omitempty"`
+	StructProperties          map[string]StructProperty          `json:"structProperties,omitempty"`
+	StructArrayProperties     map[string]StructArrayProperty     `json:"structArrayProperties,omitempty"`
+	Int32ArrayProperties      map[string]Int32ArrayProperty      `json:"int32ArrayProperties,omitempty"`
+	Int64ArrayProperties      map[string]Int64ArrayProperty      `json:"int64ArrayProperties,omitempty"`
+	FloatArrayProperties      map[string]FloatArrayProperty      `json:"floatArrayProperties,omitempty"`
+	StrArrayProperties        map[string]StrArrayProperty        `json:"strArrayProperties,omitempty"`
+	EnumArrayProperties       map[string]EnumArrayProperty       `json:"enumArrayProperties,omitempty"`
+	ByteArrayProperties       map[string]ByteArrayProperty       `json:"byteArrayProperties,omitempty"`
+	BoolArrayProperties       map[string]BoolArrayProperty       `json:"boolArrayProperties,omitempty"`
+	MapProperties             map[string]MapProperty             `json:"mapProperties,omitempty"`
+	Uint32SetProperties       map[string]Uint32SetProperty       `json:"uint32SetProperties,omitempty"`
+	SetProperties             map[string]SetProperty             `json:"setProperties,omitempty"`
+
+	// UnknownProperties keeps properties of types not listed above as raw JSON
+	UnknownProperties map[string]json.RawMessage `json:"unknownProperties,omitempty"`
+
+	diagnostics []ParseDiagnostic
+}
+
+// arrayPropertyTypes maps the element type of a generic ArrayProperty to
+// the property type it is stored as
+var arrayPropertyTypes = map[string]string{
+	"BoolProperty":       "BoolArrayProperty",
+	"ByteProperty":       "ByteArrayProperty",
+	"EnumProperty":       "EnumArrayProperty",
+	"IntProperty":        "Int32ArrayProperty",
+	"Int32Property":      "Int32ArrayProperty",
+	"Int64Property":      "Int64ArrayProperty",
+	"FloatProperty":      "FloatArrayProperty",
+	"DoubleProperty":     "FloatArrayProperty",
+	"StrProperty":        "StrArrayProperty",
+	"NameProperty":       "StrArrayProperty",
+	"ObjectProperty":     "ObjectArrayProperty",
+	"InterfaceProperty":  "ObjectArrayProperty",
+	"SoftObjectProperty": "SoftObjectArrayProperty",
+	"StructProperty":     "StructArrayProperty",
 }
 
 // UnmarshalJSON implements custom JSON unmarshaling for PropertyContainer
@@ -406,98 +648,178 @@ func (pc *PropertyContainer) UnmarshalJSON(data []byte) error {
 
 	// Initialize maps
 	pc.BoolProperties = make(map[string]BoolProperty)
+	pc.Int8Properties = make(map[string]Int8Property)
 	pc.Int32Properties = make(map[string]Int32Property)
+	pc.Int64Properties = make(map[string]Int64Property)
 	pc.Uint32Properties = make(map[string]Uint32Property)
+	pc.Uint64Properties = make(map[string]Uint64Property)
 	pc.FloatProperties = make(map[string]FloatProperty)
+	pc.DoubleProperties = make(map[string]DoubleProperty)
 	pc.StrProperties = make(map[string]StrProperty)
+	pc.NameProperties = make(map[string]NameProperty)
+	pc.TextProperties = make(map[string]TextProperty)
 	pc.ObjectProperties = make(map[string]ObjectProperty)
 	pc.ObjectArrayProperties = make(map[string]ObjectArrayProperties)
+	pc.SoftObjectProperties = make(map[string]SoftObjectProperty)
+	pc.SoftObjectArrayProperties = make(map[string]SoftObjectArrayProperty)
 	pc.EnumProperties = make(map[string]EnumProperty)
 	pc.ByteProperties = make(map[string]ByteProperty)
 	pc.StructProperties = make(map[string]StructProperty)
 	pc.StructArrayProperties = make(map[string]StructArrayProperty)
+	pc.Int32ArrayProperties = make(map[string]Int32ArrayProperty)
+	pc.Int64ArrayProperties = make(map[string]Int64ArrayProperty)
+	pc.FloatArrayProperties = make(map[string]FloatArrayProperty)
+	pc.StrArrayProperties = make(map[string]StrArrayProperty)
+	pc.EnumArrayProperties = make(map[string]EnumArrayProperty)
+	pc.ByteArrayProperties = make(map[string]ByteArrayProperty)
+	pc.BoolArrayProperties = make(map[string]BoolArrayProperty)
 	pc.MapProperties = make(map[string]MapProperty)
 	pc.Uint32SetProperties = make(map[string]Uint32SetProperty)
+	pc.SetProperties = make(map[string]SetProperty)
+	pc.UnknownProperties = make(map[string]json.RawMessage)
+	pc.diagnostics = nil
 
 	// Parse each property based on its type
 	for name, rawProp := range rawProps {
 		var propType struct {
-			Type string `json:"type"`
+			Type    string `json:"type"`
+			Subtype string `json:"subtype"`
 		}
 
 		if err := json.Unmarshal(rawProp, &propType); err != nil {
-			continue // Skip malformed properties
+			// Skip malformed properties
+			pc.diagnostics = append(pc.diagnostics, ParseDiagnostic{Name: name, Error: err.Error(), Dropped: true})
+			continue
 		}
 
-		switch propType.Type {
-		case "BoolProperty":
-			var prop BoolProperty
-			if err := json.Unmarshal(rawProp, &prop); err == nil {
-				pc.BoolProperties[name] = prop
+		typ := propType.Type
+		if typ == "ArrayProperty" {
+			if arrayType, ok := arrayPropertyTypes[propType.Subtype]; ok {
+				typ = arrayType
 			}
+		}
+
+		var err error
+		switch typ {
+		case "BoolProperty":
+			err = decodeProperty(pc.BoolProperties, name, rawProp)
+		case "Int8Property":
+			err = decodeProperty(pc.Int8Properties, name, rawProp)
 		case "Int32Property":
-			var prop Int32Property
-			if err := json.Unmarshal(rawProp, &prop); err == nil {
-				pc.Int32Properties[name] = prop
-			}
+			err = decodeProperty(pc.Int32Properties, name, rawProp)
+		case "Int64Property":
+			err = decodeProperty(pc.Int64Properties, name, rawProp)
 		case "Uint32Property":
-			var prop Uint32Property
-			if err := json.Unmarshal(rawProp, &prop); err == nil {
-				pc.Uint32Properties[name] = prop
-			}
+			err = decodeProperty(pc.Uint32Properties, name, rawProp)
+		case "UInt64Property", "Uint64Property":
+			err = decodeProperty(pc.Uint64Properties, name, rawProp)
 		case "FloatProperty":
-			var prop FloatProperty
-			if err := json.Unmarshal(rawProp, &prop); err == nil {
-				pc.FloatProperties[name] = prop
-			}
+			err = decodeProperty(pc.FloatProperties, name, rawProp)
+		case "DoubleProperty":
+			err = decodeProperty(pc.DoubleProperties, name, rawProp)
 		case "StrProperty":
-			var prop StrProperty
-			if err := json.Unmarshal(rawProp, &prop); err == nil {
-				pc.StrProperties[name] = prop
-			}
+			err = decodeProperty(pc.StrProperties, name, rawProp)
+		case "NameProperty":
+			err = decodeProperty(pc.NameProperties, name, rawProp)
+		case "TextProperty":
+			err = decodeProperty(pc.TextProperties, name, rawProp)
 		case "ObjectProperty":
-			var prop ObjectProperty
-			if err := json.Unmarshal(rawProp, &prop); err == nil {
-				pc.ObjectProperties[name] = prop
-			}
+			err = decodeProperty(pc.ObjectProperties, name, rawProp)
 		case "ObjectArrayProperty":
-			var prop ObjectArrayProperties
-			if err := json.Unmarshal(rawProp, &prop); err == nil {
-				pc.ObjectArrayProperties[name] = prop
-			}
+			err = decodeProperty(pc.ObjectArrayProperties, name, rawProp)
+		case "SoftObjectProperty":
+			err = decodeProperty(pc.SoftObjectProperties, name, rawProp)
+		case "SoftObjectArrayProperty":
+			err = decodeProperty(pc.SoftObjectArrayProperties, name, rawProp)
 		case "EnumProperty":
-			var prop EnumProperty
-			if err := json.Unmarshal(rawProp, &prop); err == nil {
-				pc.EnumProperties[name] = prop
-			}
+			err = decodeProperty(pc.EnumProperties, name, rawProp)
 		case "ByteProperty":
-			var prop ByteProperty
-			if err := json.Unmarshal(rawProp, &prop); err == nil {
-				pc.ByteProperties[name] = prop
-			}
+			err = decodeProperty(pc.ByteProperties, name, rawProp)
 		case "StructProperty":
-			var prop StructProperty
-			if err := json.Unmarshal(rawProp, &prop); err == nil {
-				pc.StructProperties[name] = prop
-			}
+			err = decodeProperty(pc.StructProperties, name, rawProp)
 		case "StructArrayProperty":
-			var prop StructArrayProperty
-			if err := json.Unmarshal(rawProp, &prop); err == nil {
-				pc.StructArrayProperties[name] = prop
-			}
+			err = decodeProperty(pc.StructArrayProperties, name, rawProp)
+		case "Int32ArrayProperty":
+			err = decodeProperty(pc.Int32ArrayProperties, name, rawProp)
+		case "Int64ArrayProperty":
+			err = decodeProperty(pc.Int64ArrayProperties, name, rawProp)
+		case "FloatArrayProperty", "DoubleArrayProperty":
+			err = decodeProperty(pc.FloatArrayProperties, name, rawProp)
+		case "StrArrayProperty":
+			err = decodeProperty(pc.StrArrayProperties, name, rawProp)
+		case "EnumArrayProperty":
+			err = decodeProperty(pc.EnumArrayProperties, name, rawProp)
+		case "ByteArrayProperty":
+			err = decodeProperty(pc.ByteArrayProperties, name, rawProp)
+		case "BoolArrayProperty":
+			err = decodeProperty(pc.BoolArrayProperties, name, rawProp)
 		case "MapProperty":
-			var prop MapProperty
-			if err := json.Unmarshal(rawProp, &prop); err == nil {
-				pc.MapProperties[name] = prop
-			}
+			err = decodeProperty(pc.MapProperties, name, rawProp)
 		case "Uint32SetProperty":
-			var prop Uint32SetProperty
-			if err := json.Unmarshal(rawProp, &prop); err == nil {
-				pc.Uint32SetProperties[name] = prop
-			}
+			err = decodeProperty(pc.Uint32SetProperties, name, rawProp)
+		case "SetProperty", "Int32SetProperty", "ObjectSetProperty", "StrSetProperty", "StructSetProperty":
+			err = decodeProperty(pc.SetProperties, name, rawProp)
+		default:
+			pc.UnknownProperties[name] = rawProp
+			pc.diagnostics = append(pc.diagnostics, ParseDiagnostic{Name: name, Type: propType.Type, Error: "unknown property type"})
+		}
+
+		if err != nil {
+			pc.diagnostics = append(pc.diagnostics, ParseDiagnostic{Name: name, Type: propType.Type, Error: err.Error(), Dropped: true})
 		}
 	}
 
+	for name, prop := range pc.StructProperties {
+		pc.diagnostics = append(pc.diagnostics, nestedDiagnostics(name, prop.Value)...)
+	}
+	for name, prop := range pc.StructArrayProperties {
+		for i, value := range prop.Values {
+			pc.diagnostics = append(pc.diagnostics, nestedDiagnostics(fmt.Sprintf("%s[%d]", name, i), value)...)
+		}
+	}
+	for name, prop := range pc.MapProperties {
+		for i, value := range prop.Values {
+			pc.diagnostics = append(pc.diagnostics, nestedDiagnostics(fmt.Sprintf("%s[%d]", name, i), value)...)
+		}
+	}
+
+	return nil
+}
+
+// decodeProperty unmarshals a single property into its typed map
+func decodeProperty[T any](props map[string]T, name string, raw json.RawMessage) error {
+	var prop T
+	if err := json.Unmarshal(raw, &prop); err != nil {
+		return err
+	}
+	props[name] = prop
+	return nil
+}
+
+// Int64Value is a 64-bit integer that may be serialized as a JSON string
+// to avoid losing precision
+type Int64Value int64
+
+// UnmarshalJSON implements custom JSON unmarshaling for quoted or bare integers
+func (v *Int64Value) UnmarshalJSON(data []byte) error {
+	i, err := strconv.ParseInt(strings.Trim(string(data), `"`), 10, 64)
+	if err != nil {
+		return err
+	}
+	*v = Int64Value(i)
+	return nil
+}
+
+// Uint64Value is an unsigned 64-bit integer that may be serialized as a JSON string
+type Uint64Value uint64
+
+// UnmarshalJSON implements custom JSON unmarshaling for quoted or bare integers
+func (v *Uint64Value) UnmarshalJSON(data []byte) error {
+	i, err := strconv.ParseUint(strings.Trim(string(data), `"`), 10, 64)
+	if err != nil {
+		return err
+	}
+	*v = Uint64Value(i)
 	return nil
 }
 
@@ -0,0 +1,252 @@
+package savefile
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// spatialCellSize is the edge length of a spatial index cell in world units (100 m)
+const spatialCellSize = 10000.0
+
+// maxCell bounds cell coordinates so huge query points and radii convert to
+// int safely. It is far beyond the map, which spans a few dozen cells.
+const maxCell = 1 << 30
+
+// SpatialEntry is an object or lightweight buildable located in the world.
+// Locations and distances are in world units, 100 per meter.
+type SpatialEntry struct {
+	Name     string   `json:"name"`
+	Type     string   `json:"type"`
+	TypePath string   `json:"typePath"`
+	Location Vector3D `json:"location"`
+	Distance float64  `json:"distance"`
+
+	// Object is set for game objects and Lightweight for lightweight buildables
+	Object      *GameObject           `json:"-"`
+	Lightweight *LightweightBuildable `json:"-"`
+}
+
+type cellKey struct {
+	x, y int
+}
+
+// spatialIndex buckets entries into a uniform grid over the map's X/Y plane
+type spatialIndex struct {
+	entries []SpatialEntry
+	cells   map[cellKey][]int
+}
+
+// Nearest returns up to n entries closest to point, nearest first. When types
+// are given only entries whose Type or TypePath matches one of them are returned.
+func (sf SaveFile) Nearest(point Vector3D, n int, types ...string) []SpatialEntry {
+	if sf.spatial == nil || n <= 0 {
+		return nil
+	}
+	return sf.spatial.nearest(point, n, typeFilter(types))
+}
+
+// Within returns every entry within radius of point, nearest first
+func (sf SaveFile) Within(point Vector3D, radius float64, types ...string) []SpatialEntry {
+	if sf.spatial == nil || radius < 0 {
+		return nil
+	}
+
+	match := typeFilter(types)
+	var result []SpatialEntry
+	sf.spatial.visit(cellOf(Vector3D{X: point.X - radius, Y: point.Y - radius}), cellOf(Vector3D{X: point.X + radius, Y: point.Y + radius}), func(entry SpatialEntry) {
+		entry.Distance = Distance(point, entry.Location)
+		if entry.Distance <= radius && match(entry) {
+			result = append(result, entry)
+		}
+	})
+	sortByDistance(result)
+	return result
+}
+
+// InBox returns every entry inside the box spanned by two corners, ordered by name
+func (sf SaveFile) InBox(a, b Vector3D, types ...string) []SpatialEntry {
+	if sf.spatial == nil {
+		return nil
+	}
+
+	min := Vector3D{X: math.Min(a.X, b.X), Y: math.Min(a.Y, b.Y), Z: math.Min(a.Z, b.Z)}
+	max := Vector3D{X: math.Max(a.X, b.X), Y: math.Max(a.Y, b.Y), Z: math.Max(a.Z, b.Z)}
+
+	match := typeFilter(types)
+	var result []SpatialEntry
+	sf.spatial.visit(cellOf(min), cellOf(max), func(entry SpatialEntry) {
+		loc := entry.Location
+		if loc.X < min.X || loc.X > max.X || loc.Y < min.Y || loc.Y > max.Y || loc.Z < min.Z || loc.Z > max.Z {
+			return
+		}
+		if match(entry) {
+			result = append(result, entry)
+		}
+	})
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// buildSpatialIndex places every located object and lightweight buildable in the grid
+func (sf *SaveFile) buildSpatialIndex() {
+	idx := &spatialIndex{cells: make(map[cellKey][]int)}
+
+	for _, typePath := range sf.typePaths {
+		for _, obj := range sf.typeIndex[typePath] {
+			// Components and subsystems have no transform and sit at the origin
+			if obj.Transform.Translation == (Vector3D{}) {
+				continue
+			}
+			idx.add(SpatialEntry{
+				Name:     obj.InstanceName,
+				Type:     obj.SimpleType(),
+				TypePath: obj.TypePath,
+				Location: obj.Transform.Translation,
+				Object:   obj,
+			})
+		}
+	}
+
+	for i := range sf.lightweightBuildables {
+		buildable := &sf.lightweightBuildables[i]
+		idx.add(SpatialEntry{
+			Name:        fmt.Sprintf("%s#%d", buildable.SimpleType(), buildable.Index),
+			Type:        buildable.SimpleType(),
+			TypePath:    buildable.TypePath,
+			Location:    buildable.Transform.Translation,
+			Lightweight: buildable,
+		})
+	}
+
+	sf.spatial = idx
+}
+
+func (idx *spatialIndex) add(entry SpatialEntry) {
+	key := cellOf(entry.Location)
+	idx.cells[key] = append(idx.cells[key], len(idx.entries))
+	idx.entries = append(idx.entries, entry)
+}
+
+// visit calls fn for every entry in the cells between min and max inclusive,
+// scanning the occupied cells instead when the range covers more of them
+func (idx *spatialIndex) visit(min, max cellKey, fn func(SpatialEntry)) {
+	span := float64(max.x-min.x+1) * float64(max.y-min.y+1)
+	if span > float64(len(idx.cells)) {
+		for key, entries := range idx.cells {
+			if key.x < min.x || key.x > max.x || key.y < min.y || key.y > max.y {
+				continue
+			}
+			for _, i := range entries {
+				fn(idx.entries[i])
+			}
+		}
+		return
+	}
+
+	for x := min.x; x <= max.x; x++ {
+		for y := min.y; y <= max.y; y++ {
+			for _, i := range idx.cells[cellKey{x, y}] {
+				fn(idx.entries[i])
+			}
+		}
+	}
+}
+
+// nearest searches rings of cells outward from the point until the n closest
+// matches are known to be found
+func (idx *spatialIndex) nearest(point Vector3D, n int, match func(SpatialEntry) bool) []SpatialEntry {
+	center := cellOf(point)
+	var found []SpatialEntry
+	collect := func(entry SpatialEntry) {
+		if match(entry) {
+			entry.Distance = Distance(point, entry.Location)
+			found = append(found, entry)
+		}
+	}
+
+	for ring := 0; ; ring++ {
+		// Once a ring covers more cells than are occupied, scan everything
+		if side := float64(2*ring + 1); side*side > float64(len(idx.cells)) {
+			found = nil
+			for _, entry := range idx.entries {
+				collect(entry)
+			}
+			break
+		}
+
+		for x := center.x - ring; x <= center.x+ring; x++ {
+			for y := center.y - ring; y <= center.y+ring; y++ {
+				if x != center.x-ring && x != center.x+ring && y != center.y-ring && y != center.y+ring {
+					continue
+				}
+				for _, i := range idx.cells[cellKey{x, y}] {
+					collect(idx.entries[i])
+				}
+			}
+		}
+
+		// Anything outside the searched square is at least ring cells away
+		sortByDistance(found)
+		if len(found) >= n && found[n-1].Distance <= float64(ring)*spatialCellSize {
+			break
+		}
+	}
+
+	sortByDistance(found)
+	if len(found) > n {
+		found = found[:n]
+	}
+	return found
+}
+
+func cellOf(v Vector3D) cellKey {
+	return cellKey{x: cellCoord(v.X), y: cellCoord(v.Y)}
+}
+
+// cellCoord converts a coordinate to its cell, clamped to maxCell since
+// converting an out of range float to int is implementation-defined
+func cellCoord(v float64) int {
+	c := math.Floor(v / spatialCellSize)
+	switch {
+	case c >= maxCell:
+		return maxCell
+	case c > -maxCell:
+		return int(c)
+	default:
+		// Also catches NaN, which compares false against both bounds
+		return -maxCell
+	}
+}
+
+// typeFilter matches entries whose Type or TypePath is one of types, or every
+// entry when no types are given
+func typeFilter(types []string) func(SpatialEntry) bool {
+	if len(types) == 0 {
+		return func(SpatialEntry) bool { return true }
+	}
+	wanted := make(map[string]bool, len(types))
+	for _, t := range types {
+		wanted[t] = true
+	}
+	return func(entry SpatialEntry) bool {
+		return wanted[entry.Type] || wanted[entry.TypePath]
+	}
+}
+
+func sortByDistance(entries []SpatialEntry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Distance == entries[j].Distance {
+			return entries[i].Name < entries[j].Name
+		}
+		return entries[i].Distance < entries[j].Distance
+	})
+}
+
+// Distance returns the straight line distance between two points in world units
+func Distance(a, b Vector3D) float64 {
+	dx, dy, dz := a.X-b.X, a.Y-b.Y, a.Z-b.Z
+	return math.Sqrt(dx*dx + dy*dy + dz*dz)
+}
@@ -0,0 +1,117 @@
+package savefile
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// spatialSave builds a save with one object of typePath at each location
+func spatialSave(t *testing.T, objects map[string]Vector3D, typePaths map[string]string) *SaveFile {
+	t.Helper()
+	var parts []string
+	for name, loc := range objects {
+		parts = append(parts, fmt.Sprintf(
+			`{"typePath":%q,"instanceName":%q,"transform":{"translation":{"x":%g,"y":%g,"z":%g}},"properties":{}}`,
+			typePaths[name], name, loc.X, loc.Y, loc.Z,
+		))
+	}
+	data := `{"header":{},"levels":{"L":{"objects":[` + strings.Join(parts, ",") + `]}}}`
+
+	var sf SaveFile
+	if err := json.Unmarshal([]byte(data), &sf); err != nil {
+		t.Fatalf("failed to decode save: %v", err)
+	}
+	return &sf
+}
+
+func entryNames(entries []SpatialEntry) []string {
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, entry.Name)
+	}
+	return names
+}
+
+var spatialObjects = map[string]Vector3D{
+	"near":      {X: 100},
+	"mid":       {X: 5000, Y: 5000},
+	"far":       {X: 50000},
+	"farther":   {X: -80000, Y: -80000},
+	"neighbour": {X: 100, Y: 9999},
+	"origin":    {},
+}
+
+var spatialTypes = map[string]string{
+	"near":      "/Game/Build_A.Build_A_C",
+	"mid":       "/Game/Build_B.Build_B_C",
+	"far":       "/Game/Build_A.Build_A_C",
+	"farther":   "/Game/Build_B.Build_B_C",
+	"neighbour": "/Game/Build_A.Build_A_C",
+	"origin":    "/Script/FactoryGame.FGSubsystem",
+}
+
+func TestNearest(t *testing.T) {
+	sf := spatialSave(t, spatialObjects, spatialTypes)
+	tests := []struct {
+		name  string
+		point Vector3D
+		n     int
+		types []string
+		want  []string
+	}{
+		{name: "closest first", point: Vector3D{}, n: 3, want: []string{"near", "mid", "neighbour"}},
+		{name: "every entry", point: Vector3D{}, n: 100, want: []string{"near", "mid", "neighbour", "far", "farther"}},
+		{name: "across cells", point: Vector3D{X: 49000}, n: 1, want: []string{"far"}},
+		{name: "class filter", point: Vector3D{}, n: 2, types: []string{"Build_B_C"}, want: []string{"mid", "farther"}},
+		{name: "type path filter", point: Vector3D{}, n: 5, types: []string{"/Game/Build_A.Build_A_C"}, want: []string{"near", "neighbour", "far"}},
+		{name: "no match", point: Vector3D{}, n: 5, types: []string{"Build_C_C"}, want: []string{}},
+		{name: "zero n", point: Vector3D{}, n: 0, want: []string{}},
+		{name: "point beyond the grid", point: Vector3D{X: 1e15}, n: 2, want: []string{"far", "mid"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := entryNames(sf.Nearest(tt.point, tt.n, tt.types...))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Nearest() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithin(t *testing.T) {
+	sf := spatialSave(t, spatialObjects, spatialTypes)
+	tests := []struct {
+		name   string
+		point  Vector3D
+		radius float64
+		types  []string
+		want   []string
+	}{
+		{name: "inside radius", point: Vector3D{}, radius: 8000, want: []string{"near", "mid"}},
+		{name: "boundary included", point: Vector3D{}, radius: 100, want: []string{"near"}},
+		{name: "spans cells", point: Vector3D{}, radius: 10000, want: []string{"near", "mid", "neighbour"}},
+		{name: "filtered", point: Vector3D{}, radius: 60000, types: []string{"Build_A_C"}, want: []string{"near", "neighbour", "far"}},
+		{name: "z counts", point: Vector3D{X: 100, Z: 500}, radius: 400, want: []string{}},
+		{name: "negative radius", point: Vector3D{}, radius: -1, want: []string{}},
+		{name: "huge radius", point: Vector3D{}, radius: 1e300, want: []string{"near", "mid", "neighbour", "far", "farther"}},
+		{name: "largest radius", point: Vector3D{X: 1}, radius: math.MaxFloat64, want: []string{"near", "mid", "neighbour", "far", "farther"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries := sf.Within(tt.point, tt.radius, tt.types...)
+			got := entryNames(entries)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Within() = %v, want %v", got, tt.want)
+			}
+			for _, entry := range entries {
+				if want := Distance(tt.point, entry.Location); entry.Distance != want {
+					t.Errorf("%s distance = %g, want %g", entry.Name, entry.Distance, want)
+				}
+			}
+		})
+	}
+}
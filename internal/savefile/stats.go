@@ -0,0 +1,39 @@
+package savefile
+
+import "time"
+
+// LoadStats describes what loading a save file cost
+type LoadStats struct {
+	// Decode is how long the parser backend took to convert the .sav to JSON
+	Decode time.Duration
+	// Unmarshal is how long decoding the JSON into objects took
+	Unmarshal time.Duration
+	// Index is how long building the lookup indexes took
+	Index time.Duration
+	// JSONSize is the size in bytes of the intermediate JSON file
+	JSONSize int64
+	// Allocated approximates the bytes allocated while unmarshaling and
+	// indexing. It is measured across the whole process, so loads running
+	// at the same time, such as other servers, are counted too.
+	Allocated uint64
+}
+
+// LoadStats returns the cost of loading the save file. Index is measured while
+// unmarshaling, the other values are set by the parser.
+func (sf SaveFile) LoadStats() LoadStats {
+	return sf.loadStats
+}
+
+// SetLoadStats replaces the load statistics recorded by the parser
+func (sf *SaveFile) SetLoadStats(stats LoadStats) {
+	sf.loadStats = stats
+}
+
+// ObjectsPerLevel returns the number of objects in every level
+func (sf SaveFile) ObjectsPerLevel() map[string]int {
+	counts := make(map[string]int, len(sf.Levels))
+	for name, level := range sf.Levels {
+		counts[name] = len(level.Objects)
+	}
+	return counts
+}
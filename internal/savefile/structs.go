@@ -0,0 +1,259 @@
+package savefile
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// InventoryItem is an item descriptor held in an inventory slot
+type InventoryItem struct {
+	ItemClass string `json:"itemClass"`
+}
+
+// InventoryStack is the content of a single inventory slot
+type InventoryStack struct {
+	Item     InventoryItem `json:"item"`
+	NumItems int           `json:"numItems"`
+}
+
+// ItemAmount is a number of items of one class, used for costs and deliveries
+type ItemAmount struct {
+	ItemClass string `json:"itemClass"`
+	Amount    int    `json:"amount"`
+}
+
+// Rotator represents an Unreal rotation in degrees
+type Rotator struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+	Z float64 `json:"z"`
+}
+
+// RailroadTrackPosition locates a train along a track segment
+type RailroadTrackPosition struct {
+	Root         string  `json:"root"`
+	InstanceName string  `json:"instanceName"`
+	Offset       float64 `json:"offset"`
+	Forward      float64 `json:"forward"`
+}
+
+// TimeTableStop is a single stop in a train's time table
+type TimeTableStop struct {
+	Station           ObjectReference `json:"station"`
+	DockForDuration   float64         `json:"dockForDuration"`
+	IsDurationAndRule bool            `json:"isDurationAndRule"`
+}
+
+// StructDecoder turns a decoded struct value into a concrete Go type
+type StructDecoder func(value map[string]interface{}) (interface{}, error)
+
+var (
+	structDecodersMu sync.RWMutex
+	structDecoders   = map[string]StructDecoder{
+		"InventoryItem":            decodeInventoryItem,
+		"InventoryStack":           decodeInventoryStack,
+		"ItemAmount":               decodeItemAmount,
+		"Vector":                   decodeFlat[Vector3D],
+		"Rotator":                  decodeFlat[Rotator],
+		"Quat":                     decodeFlat[Vector4D],
+		"LinearColor":              decodeFlat[LinearColor],
+		"RailroadTrackPosition":    decodeFlat[RailroadTrackPosition],
+		"TimeTableStop":            decodeTimeTableStop,
+		"FactoryCustomizationData": decodeFactoryCustomization,
+	}
+)
+
+// RegisterStructType adds or replaces the decoder for a struct subtype
+func RegisterStructType(subtype string, decoder StructDecoder) {
+	structDecodersMu.Lock()
+	defer structDecodersMu.Unlock()
+	structDecoders[subtype] = decoder
+}
+
+func structDecoder(subtype string) (StructDecoder, bool) {
+	structDecodersMu.RLock()
+	defer structDecodersMu.RUnlock()
+	decoder, ok := structDecoders[subtype]
+	return decoder, ok
+}
+
+// Decode converts the struct value into the Go type registered for its subtype
+func (p StructProperty) Decode() (interface{}, error) {
+	decoder, ok := structDecoder(p.Subtype)
+	if !ok {
+		return nil, fmt.Errorf("%s: no decoder registered for struct %q", p.Name, p.Subtype)
+	}
+	return decoder(p.Value)
+}
+
+// Decode converts every value into the Go type registered for the array's
+// subtype. Values that fail to decode are left out and their errors joined,
+// so the result holds the values that did decode even when err is not nil.
+func (p StructArrayProperty) Decode() ([]interface{}, error) {
+	decoder, ok := structDecoder(p.Subtype)
+	if !ok {
+		return nil, fmt.Errorf("%s: no decoder registered for struct %q", p.Name, p.Subtype)
+	}
+
+	result := make([]interface{}, 0, len(p.Values))
+	var errs []error
+	for i, value := range p.Values {
+		decoded, err := decoder(value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s[%d]: %w", p.Name, i, err))
+			continue
+		}
+		result = append(result, decoded)
+	}
+	return result, errors.Join(errs...)
+}
+
+// StructAs decodes the named StructProperty into T, e.g. StructAs[ItemAmount](props, "mCost")
+func StructAs[T any](pc PropertyContainer, name string) (T, error) {
+	var zero T
+	prop, err := pc.GetStruct(name)
+	if err != nil {
+		return zero, err
+	}
+	decoded, err := prop.Decode()
+	if err != nil {
+		return zero, err
+	}
+	typed, ok := decoded.(T)
+	if !ok {
+		return zero, mismatch(name, fmt.Sprintf("%T", zero), fmt.Sprintf("%T", decoded))
+	}
+	return typed, nil
+}
+
+// StructArrayAs decodes the named StructArrayProperty into a slice of T,
+// e.g. StructArrayAs[InventoryStack](props, "mInventoryStacks"). Elements
+// that fail to decode are skipped and their errors returned along with the
+// elements that did decode, so callers may use the result when err is not nil.
+func StructArrayAs[T any](pc PropertyContainer, name string) ([]T, error) {
+	prop, err := pc.GetStructArray(name)
+	if err != nil {
+		return nil, err
+	}
+	decoded, decodeErr := prop.Decode()
+	if decoded == nil {
+		return nil, decodeErr
+	}
+
+	result := make([]T, len(decoded))
+	for i, value := range decoded {
+		typed, ok := value.(T)
+		if !ok {
+			return nil, mismatch(name, fmt.Sprintf("%T", typed), fmt.Sprintf("%T", value))
+		}
+		result[i] = typed
+	}
+	return result, decodeErr
+}
+
+// decodeFlat decodes structs whose fields are stored directly on the value
+func decodeFlat[T any](value map[string]interface{}) (interface{}, error) {
+	var result T
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return result, err
+	}
+	err = json.Unmarshal(raw, &result)
+	return result, err
+}
+
+func decodeInventoryItem(value map[string]interface{}) (interface{}, error) {
+	// Newer parser versions nest the class under itemReference
+	for _, path := range []string{"itemReference.pathName", "pathName", "itemName"} {
+		if class, err := LookupString(value, path); err == nil {
+			return InventoryItem{ItemClass: class}, nil
+		}
+	}
+	return InventoryItem{}, notFound("itemReference")
+}
+
+func decodeInventoryStack(value map[string]interface{}) (interface{}, error) {
+	var stack InventoryStack
+	if numItems, err := LookupNumber(value, "NumItems"); err == nil {
+		stack.NumItems = int(numItems)
+	}
+
+	item, err := LookupPath(value, "Item")
+	if err != nil {
+		return stack, err
+	}
+	if fields, ok := item.(map[string]interface{}); ok {
+		decoded, err := decodeInventoryItem(fields)
+		if err != nil && stack.NumItems > 0 {
+			return stack, err
+		}
+		stack.Item = decoded.(InventoryItem)
+	}
+	return stack, nil
+}
+
+func decodeItemAmount(value map[string]interface{}) (interface{}, error) {
+	class, err := LookupString(value, "ItemClass.pathName")
+	if err != nil {
+		return ItemAmount{}, err
+	}
+	amount, err := LookupNumber(value, "Amount")
+	if err != nil {
+		return ItemAmount{}, err
+	}
+	return ItemAmount{ItemClass: class, Amount: int(amount)}, nil
+}
+
+func decodeTimeTableStop(value map[string]interface{}) (interface{}, error) {
+	var stop TimeTableStop
+	station, err := LookupPath(value, "Station")
+	if err != nil {
+		return stop, err
+	}
+	if ref, ok := station.(map[string]interface{}); ok {
+		stop.Station.LevelName, _ = ref["levelName"].(string)
+		stop.Station.PathName, _ = ref["pathName"].(string)
+	}
+	if duration, err := LookupNumber(value, "DockingRuleSet.DockForDuration"); err == nil {
+		stop.DockForDuration = duration
+	}
+	if rule, err := LookupPath(value, "DockingRuleSet.IsDurationAndRule"); err == nil {
+		stop.IsDurationAndRule, _ = rule.(bool)
+	}
+	return stop, nil
+}
+
+func decodeFactoryCustomization(value map[string]interface{}) (interface{}, error) {
+	var custom FactoryCustomization
+	for path, dst := range map[string]*string{
+		"SwatchDesc.pathName":      &custom.Swatch,
+		"MaterialDesc.pathName":    &custom.Material,
+		"PatternDesc.pathName":     &custom.Pattern,
+		"SkinDesc.pathName":        &custom.Skin,
+		"PaintFinishDesc.pathName": &custom.PaintFinish,
+	} {
+		if s, err := LookupString(value, path); err == nil {
+			*dst = s
+		}
+	}
+	if rotation, err := LookupNumber(value, "PatternRotation"); err == nil {
+		custom.PatternRotation = int(rotation)
+	}
+	for path, dst := range map[string]*LinearColor{
+		"OverrideColorData.PrimaryColor":   &custom.PrimaryColor,
+		"OverrideColorData.SecondaryColor": &custom.SecondaryColor,
+	} {
+		if color, err := LookupPath(value, path); err == nil {
+			if fields, ok := color.(map[string]interface{}); ok {
+				decoded, err := decodeFlat[LinearColor](fields)
+				if err != nil {
+					return custom, err
+				}
+				*dst = decoded.(LinearColor)
+			}
+		}
+	}
+	return custom, nil
+}
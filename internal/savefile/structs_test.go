@@ -0,0 +1,59 @@
+package savefile
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestStructArrayAs(t *testing.T) {
+	var pc PropertyContainer
+	err := json.Unmarshal([]byte(`{
+		"mCosts": {"type": "StructArrayProperty", "subtype": "ItemAmount", "values": [
+			{"ItemClass": {"pathName": "/Game/Desc_Screw.Desc_Screw_C"}, "Amount": 5},
+			{"Amount": 3},
+			{"ItemClass": {"pathName": "/Game/Desc_Wire.Desc_Wire_C"}, "Amount": 2}
+		]},
+		"mEmpty": {"type": "StructArrayProperty", "subtype": "ItemAmount", "values": []},
+		"mUnknown": {"type": "StructArrayProperty", "subtype": "NoSuchStruct", "values": [{}]},
+		"mCount": {"type": "Int32Property", "value": 1}
+	}`), &pc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		want    []ItemAmount
+		wantErr bool
+		errIs   error
+	}{
+		{
+			name: "mCosts",
+			want: []ItemAmount{
+				{ItemClass: "/Game/Desc_Screw.Desc_Screw_C", Amount: 5},
+				{ItemClass: "/Game/Desc_Wire.Desc_Wire_C", Amount: 2},
+			},
+			wantErr: true,
+		},
+		{name: "mEmpty", want: []ItemAmount{}},
+		{name: "mUnknown", wantErr: true},
+		{name: "mCount", wantErr: true, errIs: ErrTypeMismatch},
+		{name: "mMissing", wantErr: true, errIs: ErrPropertyNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := StructArrayAs[ItemAmount](pc, tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("StructArrayAs(%q) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+			if tt.errIs != nil && !errors.Is(err, tt.errIs) {
+				t.Errorf("StructArrayAs(%q) error = %v, want %v", tt.name, err, tt.errIs)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("StructArrayAs(%q) = %+v, want %+v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
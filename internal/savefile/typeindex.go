@@ -0,0 +1,62 @@
+package savefile
+
+import (
+	"sort"
+	"strings"
+)
+
+// ObjectsOfType returns every object whose TypePath or SimpleType equals t,
+// e.g. "/Script/FactoryGame.FGPowerInfoComponent" or "FGPowerInfoComponent"
+func (sf SaveFile) ObjectsOfType(t string) []*GameObject {
+	if objects, ok := sf.typeIndex[t]; ok {
+		return objects
+	}
+	return sf.simpleTypeIndex[t]
+}
+
+// ObjectsMatching returns every object whose TypePath or SimpleType starts with prefix
+func (sf SaveFile) ObjectsMatching(prefix string) []*GameObject {
+	var result []*GameObject
+	for _, typePath := range sf.typePaths {
+		objects := sf.typeIndex[typePath]
+		if strings.HasPrefix(typePath, prefix) || strings.HasPrefix(objects[0].SimpleType(), prefix) {
+			result = append(result, objects...)
+		}
+	}
+	return result
+}
+
+// TypeCounts returns the number of objects of each TypePath
+func (sf SaveFile) TypeCounts() map[string]int {
+	counts := make(map[string]int, len(sf.typeIndex))
+	for typePath, objects := range sf.typeIndex {
+		counts[typePath] = len(objects)
+	}
+	return counts
+}
+
+// buildTypeIndex groups every object by TypePath and SimpleType
+func (sf *SaveFile) buildTypeIndex() {
+	sf.typeIndex = make(map[string][]*GameObject)
+	sf.simpleTypeIndex = make(map[string][]*GameObject)
+	for _, obj := range sf.cachedObjects {
+		sf.typeIndex[obj.TypePath] = append(sf.typeIndex[obj.TypePath], obj)
+		sf.simpleTypeIndex[obj.SimpleType()] = append(sf.simpleTypeIndex[obj.SimpleType()], obj)
+	}
+
+	sf.typePaths = make([]string, 0, len(sf.typeIndex))
+	for typePath, objects := range sf.typeIndex {
+		sf.typePaths = append(sf.typePaths, typePath)
+		sortObjects(objects)
+	}
+	for _, objects := range sf.simpleTypeIndex {
+		sortObjects(objects)
+	}
+	sort.Strings(sf.typePaths)
+}
+
+func sortObjects(objects []*GameObject) {
+	sort.Slice(objects, func(i, j int) bool {
+		return objects[i].InstanceName < objects[j].InstanceName
+	})
+}